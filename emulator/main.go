@@ -17,13 +17,16 @@ limitations under the License.
 package main
 
 import (
+	"application-emulator/src/client"
 	"application-emulator/src/server"
+	"application-emulator/src/stressors"
 	"application-emulator/src/util"
 	model "application-model"
 	"encoding/json"
 	"io"
 	"os"
 	"runtime"
+	"time"
 )
 
 // Load the config map from the CONF environment variable
@@ -55,14 +58,38 @@ func main() {
 	runtime.GOMAXPROCS(configMap.Processes)
 
 	util.LoggingEnabled = configMap.Logging
+	stressors.DeadlineReduction = time.Duration(float64(configMap.DeadlineReduction) * float64(time.Second))
+	stressors.OverridesEnabled = !configMap.DisableOverrides
+	client.Addresses = configMap.Addresses
+	server.Health = configMap.Health
+	if configMap.Port == 0 {
+		configMap.Port = server.DefaultPort
+	}
 	if name, ok := os.LookupEnv("SERVICE_NAME"); ok {
 		util.ServiceName = name
 	}
 	util.LogConfiguration(configMap)
+	util.LogCalibration(stressors.CalibrateCPU())
+
+	// Persisted writes of the key-value store are restored when the service restarts
+	if configMap.StoragePath != "" {
+		records, err := stressors.OpenStorage(configMap.StoragePath)
+		if err != nil {
+			panic(err)
+		}
+		util.LogStorage(configMap.StoragePath, records)
+	}
+
+	// Background utilization is relative to the CPU limit of the service
+	cores := configMap.CpuLimit
+	if cores <= 0 {
+		cores = float64(runtime.GOMAXPROCS(0))
+	}
+	stressors.StartBackgroundStressors(configMap.Endpoints, cores)
 
 	if configMap.Protocol == "http" {
-		server.HTTP(configMap.Endpoints)
+		server.HTTP(configMap.Endpoints, configMap.Port)
 	} else if configMap.Protocol == "grpc" {
-		server.GRPC(configMap.Endpoints)
+		server.GRPC(configMap.Endpoints, configMap.Port)
 	}
 }
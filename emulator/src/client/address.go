@@ -0,0 +1,35 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import "fmt"
+
+// Addresses (host:port) of services that are not reached through their service name, such as when running locally
+var Addresses map[string]string
+
+// Returns the address requests to a service are sent to
+func Address(service string, port int) string {
+	if address, ok := Addresses[service]; ok {
+		return address
+	}
+
+	// Omit the port if zero
+	if port == 0 {
+		return service
+	}
+	return fmt.Sprintf("%s:%d", service, port)
+}
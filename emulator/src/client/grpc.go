@@ -20,7 +20,6 @@ import (
 	"application-emulator/src/generated/client"
 	"application-model/generated"
 	"context"
-	"fmt"
 	"time"
 
 	"google.golang.org/grpc"
@@ -28,14 +27,8 @@ import (
 )
 
 // Sends a gRPC request to the specified endpoint
-func GRPC(service, endpoint string, port int, payload string) (*generated.Response, error) {
-	var url string
-	// Omit the port if zero
-	if port == 0 {
-		url = service
-	} else {
-		url = fmt.Sprintf("%s:%d", service, port)
-	}
+func GRPC(ctx context.Context, service, endpoint string, port int, payload string) (*generated.Response, error) {
+	url := Address(service, port)
 
 	// TODO: TLS?
 	dialOptions := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
@@ -46,7 +39,7 @@ func GRPC(service, endpoint string, port int, payload string) (*generated.Respon
 	}
 	defer conn.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
 	defer cancel()
 
 	callOptions := []grpc.CallOption{}
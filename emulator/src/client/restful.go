@@ -17,28 +17,36 @@ limitations under the License.
 package client
 
 import (
+	model "application-model"
 	"application-model/generated"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
 const useProtoJSON = true
 
-// Sends a HTTP POST request to the specified endpoint
-func POST(service, endpoint string, port int, payload string, headers http.Header) (int, *generated.Response, error) {
-	var url string
-	// Omit the port if zero
-	if port == 0 {
-		url = fmt.Sprintf("http://%s/%s", service, endpoint)
-	} else {
-		url = fmt.Sprintf("http://%s:%d/%s", service, port, endpoint)
+// Returns the timeout in the timeout header of an inbound request, if there is one
+func RequestTimeout(headers http.Header) (time.Duration, bool) {
+	milliseconds, err := strconv.ParseInt(headers.Get(model.TimeoutHeader), 10, 64)
+	if err != nil {
+		return 0, false
 	}
 
+	return time.Duration(milliseconds) * time.Millisecond, true
+}
+
+// Sends a HTTP POST request to the specified endpoint
+func POST(ctx context.Context, service, endpoint string, port int, payload string, headers http.Header) (int, *generated.Response, error) {
+	url := fmt.Sprintf("http://%s/%s", Address(service, port), endpoint)
+
 	var postData []byte
 
 	if useProtoJSON {
@@ -48,7 +56,7 @@ func POST(service, endpoint string, port int, payload string, headers http.Heade
 		postData, _ = json.Marshal(&generated.Request{Payload: payload})
 	}
 
-	request, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(postData))
+	request, _ := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(postData))
 
 	// Forward any other headers set by the user
 	for key, values := range headers {
@@ -57,6 +65,11 @@ func POST(service, endpoint string, port int, payload string, headers http.Heade
 		}
 	}
 
+	// Propagate the deadline to the called service
+	if deadline, ok := ctx.Deadline(); ok {
+		request.Header.Set(model.TimeoutHeader, strconv.FormatInt(time.Until(deadline).Milliseconds(), 10))
+	}
+
 	// Send the request
 	response, err := http.DefaultClient.Do(request)
 	if err != nil {
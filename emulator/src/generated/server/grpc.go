@@ -25,9 +25,12 @@ import (
 	model "application-model"
 	generated_model "application-model/generated"
 	"context"
+	"fmt"
 	"log"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type Service1ServerImpl struct {
@@ -37,11 +40,26 @@ type Service1ServerImpl struct {
 
 func (s *Service1ServerImpl) TestEndpoint(ctx context.Context, request *generated_model.Request) (*generated_model.Response, error) {
 	trace := util.TraceEndpointCall(s.TestEndpointInfo, "gRPC")
+	ctx, err := stressors.ApplyOverrides(ctx, request, s.TestEndpointInfo)
+	payload := ""
+	if err == nil {
+		payload, err = stressors.ReadRequest(request, s.TestEndpointInfo)
+	}
+	if err != nil {
+		util.LogEndpointCall(trace, err)
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid request: %s", err)
+	}
 	response := &generated_model.Response{
 		Endpoint: s.TestEndpointInfo.Name,
-		Tasks:    stressors.Exec(request, s.TestEndpointInfo),
+		Tasks:    stressors.Exec(ctx, request, payload, s.TestEndpointInfo),
+	}
+	if err := ctx.Err(); err != nil {
+		response.Message = fmt.Sprintf("Request aborted: %s", err)
+	} else if overrides := stressors.OverridesFrom(ctx); overrides != nil && overrides.Error != 0 {
+		util.LogEndpointCall(trace, nil)
+		return nil, status.Errorf(overrides.ErrorCode(), "Forced error: %d", overrides.Error)
 	}
-	util.LogEndpointCall(trace)
+	util.LogEndpointCall(trace, ctx.Err())
 	return response, nil
 }
 
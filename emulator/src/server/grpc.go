@@ -24,6 +24,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -40,24 +41,46 @@ type HealthServerImpl struct {
 
 func (h *HealthServerImpl) Check(ctx context.Context, request *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
 	grpcServiceName := fmt.Sprintf("generated.%s", strcase.ToCamel(util.ServiceName))
-	if request.Service == "" || request.Service == grpcServiceName {
-		return &grpc_health_v1.HealthCheckResponse{
-			Status: grpc_health_v1.HealthCheckResponse_SERVING,
-		}, nil
-	} else {
+
+	var healthy bool
+	switch request.Service {
+	case "", grpcServiceName:
+		healthy = ready()
+	case livenessService:
+		healthy = live()
+	case startupService:
+		healthy = started()
+	default:
 		// https://github.com/grpc/grpc/blob/master/src/proto/grpc/health/v1/health.proto#L44
 		return nil, status.Errorf(codes.NotFound, "only serving %s", grpcServiceName)
 	}
+
+	if !healthy {
+		return &grpc_health_v1.HealthCheckResponse{
+			Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+		}, nil
+	}
+	return &grpc_health_v1.HealthCheckResponse{
+		Status: grpc_health_v1.HealthCheckResponse_SERVING,
+	}, nil
+}
+
+// Counts the requests to the endpoints, which are in the generated package, for the health checks
+func trackingInterceptor(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if strings.HasPrefix(info.FullMethod, "/generated.") {
+		defer trackRequest()()
+	}
+	return handler(ctx, request)
 }
 
 // Launch a gRPC server to serve one or more endpoints
-func GRPC(endpoints []model.Endpoint) {
-	listener, err := net.Listen("tcp", ":5000")
+func GRPC(endpoints []model.Endpoint, port int) {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
 		panic(err)
 	}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(trackingInterceptor))
 	server.RegisterGeneratedService(grpcServer, endpoints)
 	reflection.Register(grpcServer)
 	grpc_health_v1.RegisterHealthServer(grpcServer, &HealthServerImpl{})
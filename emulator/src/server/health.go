@@ -0,0 +1,85 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	model "application-model"
+	"application-model/generated"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Paths of the HTTP health checks besides readiness, which is checked at the root path
+const livenessPath = "/healthz/live"
+const startupPath = "/healthz/startup"
+
+// gRPC health services of the health checks besides readiness, which is checked with the default service
+const livenessService = "liveness"
+const startupService = "startup"
+
+// Ways the health checks fail on purpose, or nil if they always succeed
+var Health *model.Health
+
+var startTime = time.Now()
+var requests atomic.Int64
+var inFlight atomic.Int64
+
+// Counts a request to an endpoint, which is in flight until the returned function is called
+func trackRequest() func() {
+	requests.Add(1)
+	inFlight.Add(1)
+	return func() {
+		inFlight.Add(-1)
+	}
+}
+
+// Returns true once the startup delay has passed
+func started() bool {
+	return Health == nil || time.Since(startTime).Seconds() >= Health.StartupDelay
+}
+
+// Returns true if the service has started, is not overloaded and has not served the requests it becomes unready after
+func ready() bool {
+	if Health != nil && Health.MaxInFlight > 0 && inFlight.Load() > int64(Health.MaxInFlight) {
+		return false
+	}
+	if Health != nil && Health.UnreadyAfter > 0 && requests.Load() >= int64(Health.UnreadyAfter) {
+		return false
+	}
+	return started()
+}
+
+// Returns true if the service has started and has not served the requests it becomes unhealthy after
+// Only a restart of the service makes it healthy again
+func live() bool {
+	if Health != nil && Health.UnhealthyAfter > 0 && requests.Load() >= int64(Health.UnhealthyAfter) {
+		return false
+	}
+	return started()
+}
+
+// Returns a handler that responds to a HTTP health check with the result of check
+func healthHandler(check func() bool) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if check() {
+			writeJSONResponse(http.StatusOK, &generated.Response{}, writer)
+		} else {
+			writeJSONResponse(http.StatusServiceUnavailable, &generated.Response{Message: "Health check failed"}, writer)
+		}
+	}
+}
@@ -17,10 +17,12 @@ limitations under the License.
 package server
 
 import (
+	"application-emulator/src/client"
 	"application-emulator/src/stressors"
 	"application-emulator/src/util"
 	model "application-model"
 	"application-model/generated"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -32,6 +34,9 @@ import (
 
 const useProtoJSON = true
 
+// Port the emulator listens on unless the config map specifies another one
+const DefaultPort = 5000
+
 // Send a response of type application/json
 func writeJSONResponse(status int, response *generated.Response, writer http.ResponseWriter) {
 	writer.Header().Set("Content-Type", "application/json")
@@ -57,10 +62,10 @@ func writeJSONResponse(status int, response *generated.Response, writer http.Res
 	}
 }
 
-// This should always be available because the readiness probe will send a request to this address
+// The readiness probe sends requests to this address
 func rootHandler(writer http.ResponseWriter, request *http.Request) {
 	if request.URL.Path == "/" {
-		writeJSONResponse(http.StatusOK, &generated.Response{}, writer)
+		healthHandler(ready)(writer, request)
 	} else {
 		notFoundHandler(writer, request)
 	}
@@ -82,24 +87,61 @@ type endpointHandler struct {
 
 func (handler endpointHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	trace := util.TraceEndpointCall(handler.endpoint, "HTTP")
+	defer trackRequest()()
+
+	// The request context is cancelled if the client disconnects
+	ctx := request.Context()
+	if timeout, ok := client.RequestTimeout(request.Header); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// The body is read once here, since stressors executed in parallel can't share it
+	ctx, err := stressors.ApplyOverrides(ctx, request, handler.endpoint)
+	payload := ""
+	if err == nil {
+		payload, err = stressors.ReadRequest(request, handler.endpoint)
+	}
+	if err != nil {
+		writeJSONResponse(http.StatusBadRequest, &generated.Response{
+			Endpoint: handler.endpoint.Name,
+			Message:  fmt.Sprintf("Invalid request: %s", err),
+		}, writer)
+		util.LogEndpointCall(trace, err)
+		return
+	}
+
 	response := &generated.Response{
 		Endpoint: handler.endpoint.Name,
-		Tasks:    stressors.Exec(request, handler.endpoint),
+		Tasks:    stressors.Exec(ctx, request, payload, handler.endpoint),
+	}
+
+	status := http.StatusOK
+	if err := ctx.Err(); err != nil {
+		status = http.StatusGatewayTimeout
+		response.Message = fmt.Sprintf("Request aborted: %s", err)
+	} else if overrides := stressors.OverridesFrom(ctx); overrides != nil && overrides.Error != 0 {
+		status = overrides.Error
+		response.Message = fmt.Sprintf("Forced error: %d %s", status, http.StatusText(status))
 	}
-	writeJSONResponse(http.StatusOK, response, writer)
-	util.LogEndpointCall(trace)
+
+	writeJSONResponse(status, response, writer)
+	util.LogEndpointCall(trace, ctx.Err())
 }
 
 // Launch a HTTP server to serve one or more endpoints
-func HTTP(endpoints []model.Endpoint) {
+func HTTP(endpoints []model.Endpoint, port int) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", rootHandler)
+	mux.HandleFunc(livenessPath, healthHandler(live))
+	mux.HandleFunc(startupPath, healthHandler(started))
 
 	for i := range endpoints {
 		mux.Handle(fmt.Sprintf("/%s", endpoints[i].Name), endpointHandler{endpoint: &endpoints[i]})
 	}
 
-	err := http.ListenAndServe(":5000", mux)
+	err := http.ListenAndServe(fmt.Sprintf(":%d", port), mux)
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		panic(err)
 	}
@@ -0,0 +1,60 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stressors
+
+import (
+	model "application-model"
+	"context"
+	"math"
+	"time"
+)
+
+// Period over which background CPU utilization is kept at its target
+const utilizationPeriod = 100 * time.Millisecond
+
+// Keeps the CPU busy in the background so that the service uses a fraction utilization of cores
+// Every thread alternates between a busy loop and sleeping for the rest of the period
+func StressCPUBackground(utilization float32, cores float64) {
+	threads := int(math.Ceil(cores))
+	busyTime := float32(float64(utilization) * cores / float64(threads) * utilizationPeriod.Seconds())
+
+	for i := 0; i < threads; i++ {
+		go func() {
+			for {
+				start := time.Now()
+				StressCPU(context.Background(), busyTime, true)
+				time.Sleep(utilizationPeriod - time.Since(start))
+			}
+		}()
+	}
+}
+
+// Starts the background CPU utilization of the service if any endpoint requests it
+// Utilization is a property of the service, so validation only allows the same utilization on all endpoints and endpoints don't add up
+// Requests to these endpoints don't affect the utilization
+func StartBackgroundStressors(endpoints []model.Endpoint, cores float64) {
+	var utilization float32
+	for _, endpoint := range endpoints {
+		if endpoint.CpuComplexity != nil && endpoint.CpuComplexity.Mode == "utilization" {
+			utilization = float32(math.Max(float64(utilization), float64(endpoint.CpuComplexity.Utilization)))
+		}
+	}
+
+	if utilization > 0 {
+		StressCPUBackground(utilization, cores)
+	}
+}
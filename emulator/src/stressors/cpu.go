@@ -20,12 +20,15 @@ import (
 	"application-emulator/src/util"
 	model "application-model"
 	"application-model/generated"
+	"context"
 	"fmt"
 	"runtime"
 	"sync"
 )
 
-type CPUTask struct{}
+type CPUTask struct {
+	Payload string
+}
 
 // Combines the CPU task response in taskResponses with cpuTaskResponse
 func ConcatenateCPUResponses(taskResponses *MutexTaskResponses, cpuTaskResponse *generated.CPUTaskResponse) {
@@ -37,60 +40,119 @@ func ConcatenateCPUResponses(taskResponses *MutexTaskResponses, cpuTaskResponse
 			uniqueKey := UniqueKey(taskResponses.CpuTask.Services, k)
 			taskResponses.CpuTask.Services[uniqueKey] = v
 		}
+		taskResponses.CpuTask.Aborted = append(taskResponses.CpuTask.Aborted, cpuTaskResponse.Aborted...)
 	} else {
 		taskResponses.CpuTask = cpuTaskResponse
 	}
 }
 
 func (c *CPUTask) ExecAllowed(endpoint *model.Endpoint) bool {
-	return endpoint.CpuComplexity != nil
+	// Background utilization is started with the service and not executed per request
+	return endpoint.CpuComplexity != nil && endpoint.CpuComplexity.Mode != "utilization"
+}
+
+// Returns false if ctx was cancelled before executionTime had passed
+func StressCPU(ctx context.Context, executionTime float32, lockThread bool) bool {
+	return stressCPU(ctx, executionTime, lockThread, nil)
 }
 
-func StressCPU(executionTime float32, lockThread bool) {
+// Runs a CPU kernel until executionTime has passed
+// Kernels that process payloads are given the payload of the request
+// Returns false if ctx was cancelled before executionTime had passed
+func StressCPUKernel(ctx context.Context, executionTime float32, kernelName string, workingSetSize int, payload string, lockThread bool) bool {
+	kernel := acquireCPUKernel(kernelName, workingSetSize)
+	defer releaseCPUKernel(kernelName, workingSetSize, kernel)
+
+	if kernel, ok := kernel.(payloadKernel); ok {
+		kernel.SetPayload(payload)
+		// Cached kernels don't keep the payload of a previous request
+		defer kernel.SetPayload("")
+	}
+
+	return stressCPU(ctx, executionTime, lockThread, kernel)
+}
+
+// Runs kernel, or an empty busy loop if kernel is nil, until executionTime has passed
+func stressCPU(ctx context.Context, executionTime float32, lockThread bool, kernel cpuKernel) bool {
 	if executionTime > 0 {
 		// Threads need to be locked because otherwise util.ThreadCPUTime() can change in the middle of execution
 		if lockThread {
 			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
 		}
 
 		start := util.ThreadCPUTime()
 		target := start + int64(executionTime*1000000000.0)
+		done := ctx.Done()
 
 		for util.ThreadCPUTime() < target {
-		}
-
-		if lockThread {
-			runtime.UnlockOSThread()
+			select {
+			case <-done:
+				return false
+			default:
+			}
+
+			if kernel != nil {
+				kernel.Step()
+			}
 		}
 	}
+
+	return true
 }
 
 // Stress the CPU by running a busy loop, if the endpoint has a defined CPU complexity
-func (c *CPUTask) ExecTask(endpoint *model.Endpoint, responses *MutexTaskResponses) {
+func (c *CPUTask) ExecTask(ctx context.Context, endpoint *model.Endpoint, responses *MutexTaskResponses) {
 	stressParams := endpoint.CpuComplexity
 
+	// Returns the CPU time spent on one thread and if the stressor completed
+	stress := func() (float32, bool) {
+		if stressParams.Mode == "work" {
+			return StressWork(ctx, stressParams.WorkUnits, true)
+		} else if stressParams.Kernel != "" && stressParams.Kernel != "busy" {
+			return stressParams.ExecutionTime, StressCPUKernel(ctx, stressParams.ExecutionTime, stressParams.Kernel, stressParams.WorkingSetSize, c.Payload, true)
+		} else {
+			return stressParams.ExecutionTime, StressCPU(ctx, stressParams.ExecutionTime, true)
+		}
+	}
+
+	var executionTime float32
+	completed := true
+
 	if stressParams.Threads > 1 {
 		wg := sync.WaitGroup{}
 		wg.Add(stressParams.Threads)
+		mutex := sync.Mutex{}
 
 		for i := 0; i < stressParams.Threads; i++ {
 			go func() {
 				defer wg.Done()
-				StressCPU(stressParams.ExecutionTime, true)
+				threadTime, threadCompleted := stress()
+
+				mutex.Lock()
+				if threadTime > executionTime {
+					executionTime = threadTime
+				}
+				completed = completed && threadCompleted
+				mutex.Unlock()
 			}()
 		}
 
 		wg.Wait()
 	} else {
-		StressCPU(stressParams.ExecutionTime, true)
+		executionTime, completed = stress()
 	}
 
 	svc := fmt.Sprintf("%s/%s", util.ServiceName, endpoint.Name)
-	ConcatenateCPUResponses(responses, &generated.CPUTaskResponse{
+	cpuTaskResponse := &generated.CPUTaskResponse{
 		Services: map[string]float32{
-			svc: stressParams.ExecutionTime,
+			svc: executionTime,
 		},
-	})
+	}
+	if !completed {
+		cpuTaskResponse.Aborted = []string{svc}
+	}
+	ConcatenateCPUResponses(responses, cpuTaskResponse)
 
-	util.LogCPUTask(endpoint)
+	util.LogCPUTask(endpoint, executionTime, !completed)
 }
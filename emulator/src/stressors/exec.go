@@ -19,6 +19,7 @@ package stressors
 import (
 	model "application-model"
 	"application-model/generated"
+	"context"
 	"sync"
 )
 
@@ -32,63 +33,111 @@ type MutexTaskResponses struct {
 type Stressor interface {
 	// If the stressor should execute according to the parameters provided by the user
 	ExecAllowed(endpoint *model.Endpoint) bool
-	// Executes the workload according to user parameters
-	ExecTask(endpoint *model.Endpoint, responses *MutexTaskResponses)
+	// Executes the workload according to user parameters, aborting if ctx is cancelled
+	ExecTask(ctx context.Context, endpoint *model.Endpoint, responses *MutexTaskResponses)
+}
+
+// Returns all stressors that can be executed when a request is received
+// The payload is read from the request by the server, since stressors may run concurrently
+func Stressors(request any, payload string) []Stressor {
+	return []Stressor{
+		&PayloadTask{Payload: payload},
+		&CPUTask{Payload: payload},
+		&LockTask{},
+		&StorageTask{},
+		&NetworkTask{Request: request, Payload: payload},
+	}
 }
 
 // Executes all stressors sequentially or in parallel depending on user config
-func Exec(request any, endpoint *model.Endpoint) *generated.TaskResponses {
-	if endpoint.ExecutionMode == "parallel" {
-		return ExecParallel(request, endpoint)
+// The context of the inbound request is used to abort the stressors when the caller disconnects or the deadline expires
+// Overrides applied to ctx are executed after the stressors of the endpoint
+func Exec(ctx context.Context, request any, payload string, endpoint *model.Endpoint) *generated.TaskResponses {
+	var responses *generated.TaskResponses
+	if len(endpoint.Steps) > 0 {
+		responses = ExecSteps(ctx, request, payload, endpoint)
+	} else if endpoint.ExecutionMode == "parallel" {
+		responses = ExecParallel(ctx, request, payload, endpoint)
 	} else {
-		return ExecSequential(request, endpoint)
+		responses = ExecSequential(ctx, request, payload, endpoint)
 	}
+
+	execOverrides(ctx, endpoint, responses)
+	return responses
 }
 
 // Executes all stressors defined in the endpoint sequentially
-func ExecSequential(request any, endpoint *model.Endpoint) *generated.TaskResponses {
-	stressors := []Stressor{
-		&CPUTask{},
-		&NetworkTask{Request: request},
-	}
+func ExecSequential(ctx context.Context, request any, payload string, endpoint *model.Endpoint) *generated.TaskResponses {
 	responses := MutexTaskResponses{
 		sync.Mutex{},
 		generated.TaskResponses{},
 	}
 
+	execSequential(ctx, Stressors(request, payload), endpoint, &responses)
+	return &responses.TaskResponses
+}
+
+func execSequential(ctx context.Context, stressors []Stressor, endpoint *model.Endpoint, responses *MutexTaskResponses) {
 	for _, stressor := range stressors {
 		if stressor.ExecAllowed(endpoint) {
-			stressor.ExecTask(endpoint, &responses)
+			stressor.ExecTask(ctx, endpoint, responses)
 		}
 	}
-
-	return &responses.TaskResponses
 }
 
-func execStressor(stressor Stressor, endpoint *model.Endpoint, responses *MutexTaskResponses, wg *sync.WaitGroup) {
+func execStressor(ctx context.Context, stressor Stressor, endpoint *model.Endpoint, responses *MutexTaskResponses, wg *sync.WaitGroup) {
 	defer wg.Done()
-	stressor.ExecTask(endpoint, responses)
+	stressor.ExecTask(ctx, endpoint, responses)
 }
 
 // Executes all stressors defined in the endpoint in parallel using goroutines
-func ExecParallel(request any, endpoint *model.Endpoint) *generated.TaskResponses {
-	stressors := []Stressor{
-		&CPUTask{},
-		&NetworkTask{Request: request},
-	}
+func ExecParallel(ctx context.Context, request any, payload string, endpoint *model.Endpoint) *generated.TaskResponses {
 	responses := MutexTaskResponses{
 		sync.Mutex{},
 		generated.TaskResponses{},
 	}
+
+	execParallel(ctx, Stressors(request, payload), endpoint, &responses)
+	return &responses.TaskResponses
+}
+
+func execParallel(ctx context.Context, stressors []Stressor, endpoint *model.Endpoint, responses *MutexTaskResponses) {
 	wg := sync.WaitGroup{}
 
 	for _, stressor := range stressors {
 		if stressor.ExecAllowed(endpoint) {
 			wg.Add(1)
-			go execStressor(stressor, endpoint, &responses, &wg)
+			go execStressor(ctx, stressor, endpoint, responses, &wg)
 		}
 	}
 
 	wg.Wait()
+}
+
+// Executes the steps defined in the endpoint in order
+// The stressors in every step are executed in parallel and the next step starts when all of them are done
+func ExecSteps(ctx context.Context, request any, payload string, endpoint *model.Endpoint) *generated.TaskResponses {
+	responses := MutexTaskResponses{
+		sync.Mutex{},
+		generated.TaskResponses{},
+	}
+	stressors := Stressors(request, payload)
+
+	for i := range endpoint.Steps {
+		step := &endpoint.Steps[i]
+		// Stressors read their parameters from an endpoint, so every step is executed as one
+		stage := &model.Endpoint{
+			Name:              endpoint.Name,
+			ExecutionMode:     "parallel",
+			PayloadComplexity: step.PayloadComplexity,
+			CpuComplexity:     step.CpuComplexity,
+			LockComplexity:    step.LockComplexity,
+			StorageComplexity: step.StorageComplexity,
+			NetworkComplexity: step.NetworkComplexity,
+		}
+
+		execParallel(ctx, stressors, stage, &responses)
+	}
+
 	return &responses.TaskResponses
 }
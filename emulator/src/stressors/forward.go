@@ -20,11 +20,14 @@ import (
 	"application-emulator/src/client"
 	model "application-model"
 	"application-model/generated"
+	"context"
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -33,8 +36,25 @@ var incomingHeaders = []string{
 	"User-Agent", "End-User", "X-Request-Id", "X-B3-TraceId", "X-B3-SpanId", "X-B3-ParentSpanId", "X-B3-Sampled", "X-B3-Flags",
 }
 
-// Extract relevant headers from the source request
-func ExtractHeaders(request any) http.Header {
+// Time subtracted from the deadline of the inbound request before forwarding it
+var DeadlineReduction time.Duration
+
+// Returns a context for outbound requests, with the inbound deadline reduced by DeadlineReduction
+// Propagated overrides are added to the metadata of outbound gRPC requests
+func outboundContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	for key, value := range OverridesFrom(ctx).propagatedHeaders() {
+		ctx = metadata.AppendToOutgoingContext(ctx, key, value)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && DeadlineReduction > 0 {
+		return context.WithDeadline(ctx, deadline.Add(-DeadlineReduction))
+	}
+
+	return context.WithCancel(ctx)
+}
+
+// Extract relevant headers from the source request and the overrides that are propagated
+func ExtractHeaders(ctx context.Context, request any) http.Header {
 	// If this is a HTTP request, we should propagate the headers specified in incomingHeaders
 	httpRequest, ok := request.(*http.Request)
 	forwardHeaders := make(http.Header)
@@ -47,15 +67,19 @@ func ExtractHeaders(request any) http.Header {
 		}
 	}
 
+	for key, value := range OverridesFrom(ctx).propagatedHeaders() {
+		forwardHeaders.Set(key, value)
+	}
+
 	// Override the content type
 	forwardHeaders.Set("Content-Type", "application/json")
 
 	return forwardHeaders
 }
 
-func httpRequest(service model.CalledService, forwardHeaders http.Header) generated.EndpointResponse {
+func httpRequest(ctx context.Context, service model.CalledService, forwardHeaders http.Header, payload string) generated.EndpointResponse {
 	status, response, err :=
-		client.POST(service.Service, service.Endpoint, service.Port, RandomPayload(service.RequestPayloadSize), forwardHeaders)
+		client.POST(ctx, service.Service, service.Endpoint, service.Port, payload, forwardHeaders)
 
 	if err != nil {
 		return generated.EndpointResponse{
@@ -73,9 +97,9 @@ func httpRequest(service model.CalledService, forwardHeaders http.Header) genera
 	}
 }
 
-func grpcRequest(service model.CalledService) generated.EndpointResponse {
+func grpcRequest(ctx context.Context, service model.CalledService, payload string) generated.EndpointResponse {
 	response, err :=
-		client.GRPC(service.Service, service.Endpoint, service.Port, RandomPayload(service.RequestPayloadSize))
+		client.GRPC(ctx, service.Service, service.Endpoint, service.Port, payload)
 
 	if err != nil {
 		return generated.EndpointResponse{
@@ -93,9 +117,26 @@ func grpcRequest(service model.CalledService) generated.EndpointResponse {
 	}
 }
 
+// Returns a function that creates the payload sent to a service
+// If echo is set, the payload of the inbound request is forwarded as it was received instead of a generated one
+func outboundPayload(payload string, echo bool) func(service model.CalledService) string {
+	if echo {
+		return func(service model.CalledService) string {
+			return payload
+		}
+	}
+
+	return func(service model.CalledService) string {
+		return GeneratePayload(service.RequestPayloadSize, service.RequestPayloadContent, service.RequestPayloadCompressible)
+	}
+}
+
 // Forward requests to all services sequentially and return REST or gRPC responses
-func ForwardSequential(request any, services []model.CalledService) []generated.EndpointResponse {
-	forwardHeaders := ExtractHeaders(request)
+func ForwardSequential(ctx context.Context, request any, services []model.CalledService, payload func(service model.CalledService) string) []generated.EndpointResponse {
+	ctx, cancel := outboundContext(ctx)
+	defer cancel()
+
+	forwardHeaders := ExtractHeaders(ctx, request)
 	len := 0
 	for _, service := range services {
 		len += service.TrafficForwardRatio
@@ -106,10 +147,14 @@ func ForwardSequential(request any, services []model.CalledService) []generated.
 	for _, service := range services {
 		for j := 0; j < service.TrafficForwardRatio; j++ {
 			if service.Protocol == "http" {
-				response := httpRequest(service, forwardHeaders)
+				response := hedgedRequest(ctx, service, func(ctx context.Context) generated.EndpointResponse {
+					return httpRequest(ctx, service, forwardHeaders, payload(service))
+				})
 				responses[i] = response
 			} else if service.Protocol == "grpc" {
-				response := grpcRequest(service)
+				response := hedgedRequest(ctx, service, func(ctx context.Context) generated.EndpointResponse {
+					return grpcRequest(ctx, service, payload(service))
+				})
 				responses[i] = response
 			}
 			i++
@@ -119,23 +164,30 @@ func ForwardSequential(request any, services []model.CalledService) []generated.
 	return responses
 }
 
-func parallelHTTPRequest(responses []generated.EndpointResponse, i int, service model.CalledService, forwardHeaders http.Header, wg *sync.WaitGroup) {
+func parallelHTTPRequest(ctx context.Context, responses []generated.EndpointResponse, i int, service model.CalledService, forwardHeaders http.Header, payload string, wg *sync.WaitGroup) {
 	defer wg.Done()
-	response := httpRequest(service, forwardHeaders)
+	response := hedgedRequest(ctx, service, func(ctx context.Context) generated.EndpointResponse {
+		return httpRequest(ctx, service, forwardHeaders, payload)
+	})
 	// No mutex needed since every response has its own index
 	responses[i] = response
 }
 
-func parallelGRPCRequest(responses []generated.EndpointResponse, i int, service model.CalledService, wg *sync.WaitGroup) {
+func parallelGRPCRequest(ctx context.Context, responses []generated.EndpointResponse, i int, service model.CalledService, payload string, wg *sync.WaitGroup) {
 	defer wg.Done()
-	response := grpcRequest(service)
+	response := hedgedRequest(ctx, service, func(ctx context.Context) generated.EndpointResponse {
+		return grpcRequest(ctx, service, payload)
+	})
 	// No mutex needed since every response has its own index
 	responses[i] = response
 }
 
 // Forward requests to all services in parallel using goroutines and return REST or gRPC responses
-func ForwardParallel(request any, services []model.CalledService) []generated.EndpointResponse {
-	forwardHeaders := ExtractHeaders(request)
+func ForwardParallel(ctx context.Context, request any, services []model.CalledService, payload func(service model.CalledService) string) []generated.EndpointResponse {
+	ctx, cancel := outboundContext(ctx)
+	defer cancel()
+
+	forwardHeaders := ExtractHeaders(ctx, request)
 	len := 0
 	for _, service := range services {
 		len += service.TrafficForwardRatio
@@ -148,10 +200,10 @@ func ForwardParallel(request any, services []model.CalledService) []generated.En
 		for j := 0; j < service.TrafficForwardRatio; j++ {
 			if service.Protocol == "http" {
 				wg.Add(1)
-				go parallelHTTPRequest(responses, i, service, forwardHeaders, &wg)
+				go parallelHTTPRequest(ctx, responses, i, service, forwardHeaders, payload(service), &wg)
 			} else if service.Protocol == "grpc" {
 				wg.Add(1)
-				go parallelGRPCRequest(responses, i, service, &wg)
+				go parallelGRPCRequest(ctx, responses, i, service, payload(service), &wg)
 			}
 			i++
 		}
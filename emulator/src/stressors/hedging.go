@@ -0,0 +1,170 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stressors
+
+import (
+	model "application-model"
+	"application-model/generated"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Number of latencies kept for every called endpoint
+const latencyWindowSize = 1000
+
+// Minimum number of latencies needed before the percentile is used as hedging delay
+const latencyMinSamples = 20
+
+// Recent latencies of a called endpoint, stored as a ring buffer
+type latencyWindow struct {
+	sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+// Latencies observed for every called endpoint, keyed by service/endpoint
+var latencies sync.Map
+
+func latencyWindowFor(service model.CalledService) *latencyWindow {
+	key := fmt.Sprintf("%s/%s", service.Service, service.Endpoint)
+	window, _ := latencies.LoadOrStore(key, &latencyWindow{})
+	return window.(*latencyWindow)
+}
+
+func (w *latencyWindow) Add(latency time.Duration) {
+	w.Lock()
+	defer w.Unlock()
+
+	if len(w.samples) < latencyWindowSize {
+		w.samples = append(w.samples, latency)
+	} else {
+		w.samples[w.next] = latency
+		w.next = (w.next + 1) % latencyWindowSize
+	}
+}
+
+// Returns the p:th percentile of the stored latencies, or false if there are too few samples
+func (w *latencyWindow) Percentile(p float32) (time.Duration, bool) {
+	w.Lock()
+	if len(w.samples) < latencyMinSamples {
+		w.Unlock()
+		return 0, false
+	}
+	sorted := make([]time.Duration, len(w.samples))
+	copy(sorted, w.samples)
+	w.Unlock()
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	index := int(float32(len(sorted)-1) * p / 100.0)
+	return sorted[index], true
+}
+
+// Returns the time to wait for a response before sending a duplicate request
+func hedgingDelay(policy *model.HedgingPolicy, window *latencyWindow) (time.Duration, bool) {
+	if policy.Percentile > 0 {
+		if delay, ok := window.Percentile(policy.Percentile); ok {
+			return delay, true
+		}
+	}
+
+	// Use the fixed delay until enough latencies have been observed
+	if policy.Delay > 0 {
+		return time.Duration(float64(policy.Delay) * float64(time.Second)), true
+	}
+
+	return 0, false
+}
+
+type hedgedResponse struct {
+	response generated.EndpointResponse
+	attempt  int
+	latency  time.Duration
+}
+
+// Returns whether a called service responded successfully
+func succeeded(response generated.EndpointResponse) bool {
+	if response.Protocol == "gRPC" {
+		return response.Status == codes.OK.String()
+	}
+	return strings.HasPrefix(response.Status, "2")
+}
+
+// Sends a request and, if the called service has a hedging policy, duplicates of it when no response arrives in time
+// The first successful response is returned and all other requests are cancelled
+// If all requests sent so far fail, the last failure is returned without waiting for further duplicates
+func hedgedRequest(ctx context.Context, service model.CalledService, send func(ctx context.Context) generated.EndpointResponse) generated.EndpointResponse {
+	policy := service.Hedging
+	if policy == nil {
+		return send(ctx)
+	}
+
+	window := latencyWindowFor(service)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Buffered so that cancelled requests never block
+	results := make(chan hedgedResponse, policy.MaxHedges+1)
+	launch := func(attempt int) {
+		go func() {
+			start := time.Now()
+			response := send(ctx)
+			results <- hedgedResponse{response, attempt, time.Since(start)}
+		}()
+	}
+
+	launch(0)
+	sent := 1
+
+	var timeout <-chan time.Time
+	if delay, ok := hedgingDelay(policy, window); ok {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	completed := 0
+	for {
+		select {
+		case result := <-results:
+			// Every completed request is observed, so that the delay isn't skewed towards the fastest responses
+			window.Add(result.latency)
+			completed++
+			if !succeeded(result.response) && completed < sent {
+				continue
+			}
+
+			result.response.Hedges = sent - 1
+			result.response.HedgeWon = result.attempt > 0 && succeeded(result.response)
+			return result.response
+		case <-timeout:
+			launch(sent)
+			sent++
+
+			if sent > policy.MaxHedges {
+				timeout = nil
+			} else if delay, ok := hedgingDelay(policy, window); ok {
+				timeout = time.After(delay)
+			}
+		}
+	}
+}
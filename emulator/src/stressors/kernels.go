@@ -0,0 +1,262 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stressors
+
+import (
+	"application-model/generated"
+	"bytes"
+	"compress/flate"
+	"crypto/sha256"
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Number of bytes of the working set processed in every step of a kernel
+// Steps need to be short so that the CPU stressor can stop close to the requested execution time
+const kernelChunkSize = 4096
+
+// Number of pointers followed in every step of the pointer chasing kernel
+const pointerChaseHops = 1024
+
+// A CPU kernel executes a small part of a realistic workload every time Step is called
+type cpuKernel interface {
+	Step()
+}
+
+// A CPU kernel that processes the payload of the request instead of its working set, if the request has one
+type payloadKernel interface {
+	cpuKernel
+	SetPayload(payload string)
+}
+
+// Generates n random characters faster than RandomPayload, since working sets can be large
+func randomData(n int) []byte {
+	data := make([]byte, n)
+	var bits uint64
+	for i := range data {
+		// Use every byte of a random number
+		if i%8 == 0 {
+			bits = rand.Uint64()
+		}
+		data[i] = characters[int(bits&0xff)%len(characters)]
+		bits >>= 8
+	}
+	return data
+}
+
+// Returns a new kernel operating on a working set of workingSetSize bytes
+func newCPUKernel(name string, workingSetSize int) cpuKernel {
+	if workingSetSize < kernelChunkSize {
+		workingSetSize = kernelChunkSize
+	}
+
+	switch name {
+	case "sha256":
+		return &sha256Kernel{data: randomData(workingSetSize)}
+	case "json":
+		return &encodeKernel{workingSetSize: workingSetSize, useJSON: true}
+	case "protobuf":
+		return &encodeKernel{workingSetSize: workingSetSize}
+	case "compression":
+		writer, _ := flate.NewWriter(nil, flate.DefaultCompression)
+		return &compressionKernel{data: randomData(workingSetSize), writer: writer}
+	case "matrix":
+		return newMatrixKernel(workingSetSize)
+	case "pointer_chase":
+		return newPointerChaseKernel(workingSetSize)
+	default:
+		panic(fmt.Errorf("unknown CPU kernel %s", name))
+	}
+}
+
+// Kernels are expensive to create with large working sets, so they are kept and reused between requests
+// Every kernel is used by at most one thread at a time
+var kernelCache = struct {
+	sync.Mutex
+	kernels map[string][]cpuKernel
+}{kernels: map[string][]cpuKernel{}}
+
+func acquireCPUKernel(name string, workingSetSize int) cpuKernel {
+	key := fmt.Sprintf("%s/%d", name, workingSetSize)
+
+	kernelCache.Lock()
+	if kernels := kernelCache.kernels[key]; len(kernels) > 0 {
+		kernel := kernels[len(kernels)-1]
+		kernelCache.kernels[key] = kernels[:len(kernels)-1]
+		kernelCache.Unlock()
+		return kernel
+	}
+	kernelCache.Unlock()
+
+	return newCPUKernel(name, workingSetSize)
+}
+
+func releaseCPUKernel(name string, workingSetSize int, kernel cpuKernel) {
+	key := fmt.Sprintf("%s/%d", name, workingSetSize)
+
+	kernelCache.Lock()
+	defer kernelCache.Unlock()
+	kernelCache.kernels[key] = append(kernelCache.kernels[key], kernel)
+}
+
+// Returns the next chunk of data and moves the offset forward, wrapping around at the end
+// Data shorter than a chunk is returned as a whole
+func nextChunk(data []byte, offset *int) []byte {
+	if len(data) <= kernelChunkSize {
+		return data
+	}
+	if *offset+kernelChunkSize > len(data) {
+		*offset = 0
+	}
+	chunk := data[*offset : *offset+kernelChunkSize]
+	*offset += kernelChunkSize
+	return chunk
+}
+
+// Hashes the working set with SHA-256, one chunk at a time
+type sha256Kernel struct {
+	data   []byte
+	offset int
+	sum    [sha256.Size]byte
+}
+
+func (k *sha256Kernel) Step() {
+	k.sum = sha256.Sum256(nextChunk(k.data, &k.offset))
+}
+
+// Encodes and decodes a request containing one chunk of the request payload as payload
+// Without a request payload, chunks of a working set are used, which is only allocated when it is needed
+type encodeKernel struct {
+	workingSetSize int
+	data           []byte
+	payload        []byte
+	offset         int
+	useJSON        bool
+	decoded        generated.Request
+}
+
+func (k *encodeKernel) SetPayload(payload string) {
+	k.payload = []byte(payload)
+	k.offset = 0
+}
+
+func (k *encodeKernel) Step() {
+	source := k.payload
+	if len(source) == 0 {
+		if k.data == nil {
+			k.data = randomData(k.workingSetSize)
+		}
+		source = k.data
+	}
+	request := &generated.Request{Payload: string(nextChunk(source, &k.offset))}
+
+	if k.useJSON {
+		encoded, _ := protojson.Marshal(request)
+		protojson.Unmarshal(encoded, &k.decoded)
+	} else {
+		encoded, _ := proto.Marshal(request)
+		proto.Unmarshal(encoded, &k.decoded)
+	}
+}
+
+// Compresses the working set with DEFLATE, one chunk at a time
+type compressionKernel struct {
+	data   []byte
+	offset int
+	writer *flate.Writer
+	output bytes.Buffer
+}
+
+func (k *compressionKernel) Step() {
+	k.output.Reset()
+	k.writer.Reset(&k.output)
+	k.writer.Write(nextChunk(k.data, &k.offset))
+	k.writer.Close()
+}
+
+// Multiplies two square matrices that together with the result fill the working set
+type matrixKernel struct {
+	n       int
+	a, b, c []float64
+	cell    int
+}
+
+func newMatrixKernel(workingSetSize int) *matrixKernel {
+	n := 1
+	for 3*(n+1)*(n+1)*8 <= workingSetSize {
+		n++
+	}
+
+	k := &matrixKernel{n: n, a: make([]float64, n*n), b: make([]float64, n*n), c: make([]float64, n*n)}
+	for i := range k.a {
+		k.a[i] = rand.Float64()
+		k.b[i] = rand.Float64()
+	}
+	return k
+}
+
+// Calculates enough cells of the result to do roughly as much work as the other kernels in one step
+func (k *matrixKernel) Step() {
+	cells := kernelChunkSize / k.n
+	if cells < 1 {
+		cells = 1
+	}
+
+	for ; cells > 0; cells-- {
+		row, col := k.cell/k.n, k.cell%k.n
+		sum := 0.0
+		for i := 0; i < k.n; i++ {
+			sum += k.a[row*k.n+i] * k.b[i*k.n+col]
+		}
+		k.c[k.cell] = sum
+		k.cell = (k.cell + 1) % (k.n * k.n)
+	}
+}
+
+// Follows pointers through a random cycle covering the working set, which defeats caches and prefetching
+type pointerChaseKernel struct {
+	next    []int32
+	current int32
+}
+
+func newPointerChaseKernel(workingSetSize int) *pointerChaseKernel {
+	n := workingSetSize / 4
+	next := make([]int32, n)
+	for i := range next {
+		next[i] = int32(i)
+	}
+
+	// Sattolo's algorithm creates a random permutation that is a single cycle
+	for i := n - 1; i > 0; i-- {
+		j := rand.Intn(i)
+		next[i], next[j] = next[j], next[i]
+	}
+
+	return &pointerChaseKernel{next: next}
+}
+
+func (k *pointerChaseKernel) Step() {
+	current := k.current
+	for i := 0; i < pointerChaseHops; i++ {
+		current = k.next[current]
+	}
+	k.current = current
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stressors
+
+import (
+	"application-emulator/src/util"
+	model "application-model"
+	"application-model/generated"
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+type LockTask struct{}
+
+// Pools of locks shared by all requests, keyed by the lock complexity they were created for
+// Locks are buffered channels so that waiting for them can be aborted
+var lockPools sync.Map
+
+func lockPoolFor(stressParams *model.LockComplexity) []chan struct{} {
+	if pool, ok := lockPools.Load(stressParams); ok {
+		return pool.([]chan struct{})
+	}
+
+	pool := make([]chan struct{}, stressParams.Locks)
+	for i := range pool {
+		pool[i] = make(chan struct{}, 1)
+	}
+
+	actual, _ := lockPools.LoadOrStore(stressParams, pool)
+	return actual.([]chan struct{})
+}
+
+// Combines the lock task response in taskResponses with lockTaskResponse
+func ConcatenateLockResponses(taskResponses *MutexTaskResponses, lockTaskResponse *generated.LockTaskResponse) {
+	taskResponses.Mutex.Lock()
+	defer taskResponses.Mutex.Unlock()
+
+	if taskResponses.LockTask != nil {
+		for k, v := range lockTaskResponse.Services {
+			uniqueKey := UniqueKey(taskResponses.LockTask.Services, k)
+			taskResponses.LockTask.Services[uniqueKey] = v
+		}
+		taskResponses.LockTask.Aborted = append(taskResponses.LockTask.Aborted, lockTaskResponse.Aborted...)
+	} else {
+		taskResponses.LockTask = lockTaskResponse
+	}
+}
+
+func (l *LockTask) ExecAllowed(endpoint *model.Endpoint) bool {
+	return endpoint.LockComplexity != nil
+}
+
+// Waits for a random lock in the pool and holds it for the critical section
+// Returns the time spent waiting and false if ctx was cancelled before the critical section completed
+func StressLock(ctx context.Context, stressParams *model.LockComplexity) (time.Duration, bool) {
+	pool := lockPoolFor(stressParams)
+	lock := pool[rand.Intn(len(pool))]
+
+	start := time.Now()
+	select {
+	case lock <- struct{}{}:
+	case <-ctx.Done():
+		return time.Since(start), false
+	}
+	waitTime := time.Since(start)
+	defer func() { <-lock }()
+
+	if stressParams.HoldMode == "sleep" {
+		select {
+		case <-time.After(time.Duration(float64(stressParams.HoldTime) * float64(time.Second))):
+		case <-ctx.Done():
+			return waitTime, false
+		}
+		return waitTime, true
+	} else {
+		return waitTime, StressCPU(ctx, stressParams.HoldTime, true)
+	}
+}
+
+// Contend with other requests for a shared lock, if the endpoint has a defined lock complexity
+func (l *LockTask) ExecTask(ctx context.Context, endpoint *model.Endpoint, responses *MutexTaskResponses) {
+	stressParams := endpoint.LockComplexity
+	waitTime, completed := StressLock(ctx, stressParams)
+
+	svc := fmt.Sprintf("%s/%s", util.ServiceName, endpoint.Name)
+	lockTaskResponse := &generated.LockTaskResponse{
+		Services: map[string]float32{
+			svc: float32(waitTime.Seconds()),
+		},
+	}
+	if !completed {
+		lockTaskResponse.Aborted = []string{svc}
+	}
+	ConcatenateLockResponses(responses, lockTaskResponse)
+
+	util.LogLockTask(endpoint, waitTime.Seconds(), !completed)
+}
@@ -20,6 +20,7 @@ import (
 	"application-emulator/src/util"
 	model "application-model"
 	"application-model/generated"
+	"context"
 	"fmt"
 	"math/rand"
 	"strings"
@@ -46,6 +47,7 @@ func RandomPayload(n int) string {
 
 type NetworkTask struct {
 	Request any
+	Payload string
 }
 
 // Get a unique key for appending to map
@@ -70,6 +72,7 @@ func ConcatenateNetworkResponses(taskResponses *MutexTaskResponses, networkTaskR
 
 	if taskResponses.NetworkTask != nil {
 		taskResponses.NetworkTask.Services = append(taskResponses.NetworkTask.Services, networkTaskResponse.Services...)
+		taskResponses.NetworkTask.Aborted = append(taskResponses.NetworkTask.Aborted, networkTaskResponse.Aborted...)
 		for k, v := range networkTaskResponse.Responses {
 			uniqueKey := UniqueKey(taskResponses.NetworkTask.Responses, k)
 			taskResponses.NetworkTask.Responses[uniqueKey] = v
@@ -85,6 +88,8 @@ func ConcatenateNetworkResponses(taskResponses *MutexTaskResponses, networkTaskR
 		taskResponses.NetworkTask.Responses[uniqueKey] = &generated.ServiceResponse{
 			Protocol: r.Protocol,
 			Status:   r.Status,
+			Hedges:   int32(r.Hedges),
+			HedgeWon: r.HedgeWon,
 		}
 
 		// ResponseData is nil if an error occured
@@ -97,6 +102,15 @@ func ConcatenateNetworkResponses(taskResponses *MutexTaskResponses, networkTaskR
 			if r.ResponseData.Tasks.NetworkTask != nil {
 				ConcatenateNetworkResponses(taskResponses, r.ResponseData.Tasks.NetworkTask, nil)
 			}
+			if r.ResponseData.Tasks.LockTask != nil {
+				ConcatenateLockResponses(taskResponses, r.ResponseData.Tasks.LockTask)
+			}
+			if r.ResponseData.Tasks.PayloadTask != nil {
+				ConcatenatePayloadResponses(taskResponses, r.ResponseData.Tasks.PayloadTask)
+			}
+			if r.ResponseData.Tasks.StorageTask != nil {
+				ConcatenateStorageResponses(taskResponses, r.ResponseData.Tasks.StorageTask)
+			}
 			taskResponses.Mutex.Lock()
 		}
 	}
@@ -107,22 +121,36 @@ func (n *NetworkTask) ExecAllowed(endpoint *model.Endpoint) bool {
 }
 
 // Stress the network by returning a user-defined payload and calling other endpoints
-func (n *NetworkTask) ExecTask(endpoint *model.Endpoint, responses *MutexTaskResponses) {
+func (n *NetworkTask) ExecTask(ctx context.Context, endpoint *model.Endpoint, responses *MutexTaskResponses) {
 	stressParams := endpoint.NetworkComplexity
+	overrides := OverridesFrom(ctx)
 
+	calledServices := overrides.filterCalls(stressParams.CalledServices)
 	var calls []generated.EndpointResponse
 	if stressParams.ForwardRequests == "asynchronous" {
-		calls = ForwardParallel(n.Request, stressParams.CalledServices)
+		calls = ForwardParallel(ctx, n.Request, calledServices, outboundPayload(n.Payload, stressParams.EchoPayload))
 	} else if stressParams.ForwardRequests == "synchronous" {
-		calls = ForwardSequential(n.Request, stressParams.CalledServices)
+		calls = ForwardSequential(ctx, n.Request, calledServices, outboundPayload(n.Payload, stressParams.EchoPayload))
+	}
+
+	payloadSize := stressParams.ResponsePayloadSize
+	if overrides != nil && overrides.PayloadSize >= 0 {
+		payloadSize = overrides.PayloadSize
 	}
 
+	// Calls fail as soon as the context is done, so the task is aborted if that happened before responding
+	aborted := ctx.Err() != nil
+
 	svc := fmt.Sprintf("%s/%s", util.ServiceName, endpoint.Name)
-	ConcatenateNetworkResponses(responses, &generated.NetworkTaskResponse{
+	networkTaskResponse := &generated.NetworkTaskResponse{
 		Services:  []string{svc},
 		Responses: make(map[string]*generated.ServiceResponse),
-		Payload:   RandomPayload(stressParams.ResponsePayloadSize),
-	}, calls)
+		Payload:   GeneratePayload(payloadSize, stressParams.ResponsePayloadContent, stressParams.ResponsePayloadCompressible),
+	}
+	if aborted {
+		networkTaskResponse.Aborted = []string{svc}
+	}
+	ConcatenateNetworkResponses(responses, networkTaskResponse, calls)
 
-	util.LogNetworkTask(endpoint, calls)
+	util.LogNetworkTask(endpoint, calls, aborted)
 }
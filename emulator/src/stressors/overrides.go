@@ -0,0 +1,224 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stressors
+
+import (
+	"application-emulator/src/util"
+	model "application-model"
+	"application-model/generated"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// Headers (HTTP) or metadata keys (gRPC) that override the behavior of an endpoint for a single request
+const (
+	// Additional CPU time in seconds
+	OverrideCpuTimeHeader = "X-Override-Cpu-Time"
+	// HTTP status code the endpoint responds with after executing its stressors
+	OverrideErrorHeader = "X-Override-Error"
+	// Size of the response payload, replacing response_payload_size
+	OverridePayloadSizeHeader = "X-Override-Payload-Size"
+	// Comma-separated list of service/endpoint calls that are not made
+	OverrideDisableCallHeader = "X-Override-Disable-Call"
+	// If "true", the overrides are also sent to called services
+	OverridePropagateHeader = "X-Override-Propagate"
+)
+
+// Set to false to ignore override headers and metadata
+var OverridesEnabled = true
+
+type overridesKey struct{}
+
+// Changes to the behavior of an endpoint requested by a client
+type Overrides struct {
+	CpuTime       float32
+	Error         int
+	PayloadSize   int
+	DisabledCalls []string
+	Propagate     bool
+	// Header values as received, used to propagate the overrides
+	values map[string]string
+}
+
+// Returns the overrides of the inbound request, or nil if it has none
+// HTTP requests carry overrides in headers and gRPC requests in metadata
+func RequestOverrides(ctx context.Context, request any) (*Overrides, error) {
+	var get func(key string) string
+
+	switch request := request.(type) {
+	case *http.Request:
+		get = request.Header.Get
+	default:
+		md, _ := metadata.FromIncomingContext(ctx)
+		get = func(key string) string {
+			if values := md.Get(key); len(values) > 0 {
+				return values[0]
+			}
+			return ""
+		}
+	}
+
+	overrides := &Overrides{PayloadSize: -1, values: make(map[string]string)}
+	for _, key := range []string{OverrideCpuTimeHeader, OverrideErrorHeader, OverridePayloadSizeHeader, OverrideDisableCallHeader, OverridePropagateHeader} {
+		value := get(key)
+		if value == "" {
+			continue
+		}
+		overrides.values[key] = value
+
+		var err error
+		switch key {
+		case OverrideCpuTimeHeader:
+			var cpuTime float64
+			cpuTime, err = strconv.ParseFloat(value, 32)
+			if err == nil && cpuTime < 0 {
+				err = fmt.Errorf("negative CPU time")
+			}
+			overrides.CpuTime = float32(cpuTime)
+		case OverrideErrorHeader:
+			overrides.Error, err = strconv.Atoi(value)
+			if err == nil && (overrides.Error < 400 || overrides.Error > 599) {
+				err = fmt.Errorf("status code %d is not an error", overrides.Error)
+			}
+		case OverridePayloadSizeHeader:
+			overrides.PayloadSize, err = strconv.Atoi(value)
+			if err == nil && overrides.PayloadSize < 0 {
+				err = fmt.Errorf("negative payload size")
+			}
+		case OverrideDisableCallHeader:
+			for _, call := range strings.Split(value, ",") {
+				overrides.DisabledCalls = append(overrides.DisabledCalls, strings.TrimSpace(call))
+			}
+		case OverridePropagateHeader:
+			overrides.Propagate, err = strconv.ParseBool(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s '%s': %s", key, value, err)
+		}
+	}
+
+	if len(overrides.values) == 0 {
+		return nil, nil
+	}
+	return overrides, nil
+}
+
+// Adds the overrides of the inbound request to ctx, unless overrides are disabled
+func ApplyOverrides(ctx context.Context, request any, endpoint *model.Endpoint) (context.Context, error) {
+	if !OverridesEnabled {
+		return ctx, nil
+	}
+
+	overrides, err := RequestOverrides(ctx, request)
+	if err != nil || overrides == nil {
+		return ctx, err
+	}
+
+	util.LogOverrides(endpoint, overrides)
+	return context.WithValue(ctx, overridesKey{}, overrides), nil
+}
+
+// Returns the overrides applied to ctx, or nil if there are none
+func OverridesFrom(ctx context.Context) *Overrides {
+	overrides, _ := ctx.Value(overridesKey{}).(*Overrides)
+	return overrides
+}
+
+func (o *Overrides) String() string {
+	return fmt.Sprintf("cpuTime=%s error=%d payloadSize=%d disabledCalls=%v propagate=%t",
+		util.FormatTime(float64(o.CpuTime)), o.Error, o.PayloadSize, o.DisabledCalls, o.Propagate)
+}
+
+// Returns the gRPC status code closest to the forced HTTP error
+func (o *Overrides) ErrorCode() codes.Code {
+	switch o.Error {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
+
+// Returns the services that are called, leaving out calls disabled by the overrides
+func (o *Overrides) filterCalls(services []model.CalledService) []model.CalledService {
+	if o == nil || len(o.DisabledCalls) == 0 {
+		return services
+	}
+
+	filtered := []model.CalledService{}
+	for _, service := range services {
+		disabled := false
+		for _, call := range o.DisabledCalls {
+			if call == fmt.Sprintf("%s/%s", service.Service, service.Endpoint) {
+				disabled = true
+			}
+		}
+		if !disabled {
+			filtered = append(filtered, service)
+		}
+	}
+	return filtered
+}
+
+// Returns the headers that propagate the overrides to called services
+func (o *Overrides) propagatedHeaders() map[string]string {
+	if o == nil || !o.Propagate {
+		return nil
+	}
+	return o.values
+}
+
+// Spends the additional CPU time requested by the overrides and adds it to the CPU task response
+func execOverrides(ctx context.Context, endpoint *model.Endpoint, responses *generated.TaskResponses) {
+	overrides := OverridesFrom(ctx)
+	if overrides == nil || overrides.CpuTime <= 0 {
+		return
+	}
+
+	completed := StressCPU(ctx, overrides.CpuTime, true)
+
+	svc := fmt.Sprintf("%s/%s", util.ServiceName, endpoint.Name)
+	if responses.CpuTask == nil {
+		responses.CpuTask = &generated.CPUTaskResponse{Services: make(map[string]float32)}
+	}
+	responses.CpuTask.Services[UniqueKey(responses.CpuTask.Services, svc)] = overrides.CpuTime
+	if !completed {
+		responses.CpuTask.Aborted = append(responses.CpuTask.Aborted, svc)
+	}
+}
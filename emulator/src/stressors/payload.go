@@ -0,0 +1,292 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stressors
+
+import (
+	"application-emulator/src/util"
+	model "application-model"
+	"application-model/generated"
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Length of the sequence repeated in compressible payloads
+const compressiblePatternSize = 16
+
+// Length of the values in JSON payloads
+const jsonPayloadValueSize = 32
+
+// Generates a payload of about n characters
+// content is "text" for random lowercase characters, "json" for a JSON object or "bytes" for base64 encoded random bytes
+// Compressible payloads repeat a short random sequence instead of being random throughout
+func GeneratePayload(n int, content string, compressible bool) string {
+	if n <= 0 {
+		return ""
+	}
+
+	switch content {
+	case "json":
+		return generateJSONPayload(n, compressible)
+	case "bytes":
+		return generateBytesPayload(n, compressible)
+	default:
+		if compressible {
+			return repeatToLength(RandomPayload(compressiblePatternSize), n)
+		} else {
+			return RandomPayload(n)
+		}
+	}
+}
+
+func repeatToLength(pattern string, n int) string {
+	return strings.Repeat(pattern, n/len(pattern)+1)[:n]
+}
+
+// Generates a JSON object with string values that is about n characters long
+func generateJSONPayload(n int, compressible bool) string {
+	value := RandomPayload(jsonPayloadValueSize)
+
+	builder := strings.Builder{}
+	builder.Grow(n + jsonPayloadValueSize)
+	builder.WriteByte('{')
+
+	for i := 0; builder.Len() < n-1; i++ {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		if !compressible {
+			value = RandomPayload(jsonPayloadValueSize)
+		}
+		fmt.Fprintf(&builder, "\"field%d\":\"%s\"", i, value)
+	}
+
+	builder.WriteByte('}')
+	return builder.String()
+}
+
+// Generates base64 encoded bytes, rounding n down to a multiple of 4 so that the result is valid base64
+func generateBytesPayload(n int, compressible bool) string {
+	data := make([]byte, base64.StdEncoding.DecodedLen(n))
+	period := len(data)
+	if compressible && period > compressiblePatternSize {
+		period = compressiblePatternSize
+	}
+
+	var bits uint64
+	for i := range data {
+		if i >= period {
+			data[i] = data[i%period]
+			continue
+		}
+		// Use every byte of a random number
+		if i%8 == 0 {
+			bits = rand.Uint64()
+		}
+		data[i] = byte(bits)
+		bits >>= 8
+	}
+
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// Returns the payload of an inbound HTTP or gRPC request
+// The body of a HTTP request can only be read once, so servers call this before executing any stressors
+// If the body can't be decoded, it is returned as is together with the error
+func RequestPayload(request any) (string, error) {
+	switch request := request.(type) {
+	case *generated.Request:
+		return request.Payload, nil
+	case *http.Request:
+		if request.Body == nil {
+			return "", nil
+		}
+
+		data, err := io.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		if len(data) == 0 {
+			return "", nil
+		}
+
+		decoded := &generated.Request{}
+		if err := protojson.Unmarshal(data, decoded); err != nil {
+			return string(data), err
+		}
+		return decoded.Payload, nil
+	default:
+		return "", nil
+	}
+}
+
+// Returns the payload complexity of an endpoint and all of its steps
+func payloadComplexities(endpoint *model.Endpoint) []*model.PayloadComplexity {
+	complexities := []*model.PayloadComplexity{}
+	if endpoint.PayloadComplexity != nil {
+		complexities = append(complexities, endpoint.PayloadComplexity)
+	}
+	for _, step := range endpoint.Steps {
+		if step.PayloadComplexity != nil {
+			complexities = append(complexities, step.PayloadComplexity)
+		}
+	}
+	return complexities
+}
+
+// Checks that the payload has the expected content type
+func validatePayload(payload, contentType string) error {
+	switch contentType {
+	case "json":
+		var object map[string]any
+		if err := json.Unmarshal([]byte(payload), &object); err != nil {
+			return fmt.Errorf("payload is not a JSON object: %s", err)
+		}
+	case "bytes":
+		if _, err := base64.StdEncoding.DecodeString(payload); err != nil {
+			return fmt.Errorf("payload is not base64 encoded: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// Reads the payload of an inbound request and validates it, if the endpoint requires it
+// Servers call this before executing any stressors and reject the request if it fails
+// Invalid payloads are returned as they are, since validation is optional
+func ReadRequest(request any, endpoint *model.Endpoint) (string, error) {
+	payload, decodeErr := RequestPayload(request)
+
+	for _, stressParams := range payloadComplexities(endpoint) {
+		if !stressParams.Validate {
+			continue
+		}
+
+		if decodeErr != nil {
+			return payload, decodeErr
+		}
+		if err := validatePayload(payload, stressParams.ContentType); err != nil {
+			return payload, err
+		}
+	}
+
+	return payload, nil
+}
+
+// Transforms the payload and returns the size of the result
+func transformPayload(payload, transform string) (int, error) {
+	switch transform {
+	case "compress":
+		output := bytes.Buffer{}
+		writer, _ := flate.NewWriter(&output, flate.DefaultCompression)
+		writer.Write([]byte(payload))
+		writer.Close()
+		return output.Len(), nil
+	case "base64":
+		return len(base64.StdEncoding.EncodeToString([]byte(payload))), nil
+	case "json":
+		// Payloads that are not JSON are encoded as a JSON string
+		var decoded any
+		if err := json.Unmarshal([]byte(payload), &decoded); err != nil {
+			decoded = payload
+		}
+		encoded, err := json.Marshal(decoded)
+		return len(encoded), err
+	case "", "none":
+		return len(payload), nil
+	default:
+		return 0, errors.New("unknown transform " + transform)
+	}
+}
+
+type PayloadTask struct {
+	Payload string
+}
+
+// Combines the payload task response in taskResponses with payloadTaskResponse
+func ConcatenatePayloadResponses(taskResponses *MutexTaskResponses, payloadTaskResponse *generated.PayloadTaskResponse) {
+	taskResponses.Mutex.Lock()
+	defer taskResponses.Mutex.Unlock()
+
+	if taskResponses.PayloadTask != nil {
+		for k, v := range payloadTaskResponse.Services {
+			uniqueKey := UniqueKey(taskResponses.PayloadTask.Services, k)
+			taskResponses.PayloadTask.Services[uniqueKey] = v
+		}
+		for k, v := range payloadTaskResponse.Hashes {
+			if taskResponses.PayloadTask.Hashes == nil {
+				taskResponses.PayloadTask.Hashes = make(map[string]string)
+			}
+			uniqueKey := UniqueKey(taskResponses.PayloadTask.Hashes, k)
+			taskResponses.PayloadTask.Hashes[uniqueKey] = v
+		}
+		taskResponses.PayloadTask.Aborted = append(taskResponses.PayloadTask.Aborted, payloadTaskResponse.Aborted...)
+	} else {
+		taskResponses.PayloadTask = payloadTaskResponse
+	}
+}
+
+func (p *PayloadTask) ExecAllowed(endpoint *model.Endpoint) bool {
+	return endpoint.PayloadComplexity != nil
+}
+
+// Process the payload of the inbound request, if the endpoint has a defined payload complexity
+func (p *PayloadTask) ExecTask(ctx context.Context, endpoint *model.Endpoint, responses *MutexTaskResponses) {
+	stressParams := endpoint.PayloadComplexity
+	payload := p.Payload
+
+	svc := fmt.Sprintf("%s/%s", util.ServiceName, endpoint.Name)
+	payloadTaskResponse := &generated.PayloadTaskResponse{
+		Services: map[string]int32{
+			svc: int32(len(payload)),
+		},
+	}
+
+	if stressParams.Hash {
+		sum := sha256.Sum256([]byte(payload))
+		payloadTaskResponse.Hashes = map[string]string{
+			svc: hex.EncodeToString(sum[:]),
+		}
+	}
+
+	transformedSize, err := transformPayload(payload, stressParams.Transform)
+	if err != nil {
+		transformedSize = 0
+	}
+
+	// Additional CPU time proportional to the payload size
+	executionTime := stressParams.CpuTimePerKb * float32(len(payload)) / 1024.0
+	if !StressCPU(ctx, executionTime, true) {
+		payloadTaskResponse.Aborted = []string{svc}
+	}
+	ConcatenatePayloadResponses(responses, payloadTaskResponse)
+
+	util.LogPayloadTask(endpoint, len(payload), transformedSize, len(payloadTaskResponse.Aborted) > 0)
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stressors
+
+import (
+	"application-emulator/src/util"
+	model "application-model"
+	"application-model/generated"
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Name of the file in the storage directory that persisted writes are appended to
+const storageLogName = "store.log"
+
+// Size of the key and value length that precede every value in the log
+const storageRecordHeaderSize = 12
+
+type StorageTask struct{}
+
+// Key-value store shared by all endpoints of the service
+type kvStore struct {
+	mutex  sync.RWMutex
+	values map[uint64][]byte
+	size   int64
+	log    *os.File
+}
+
+var store = kvStore{values: make(map[uint64][]byte)}
+
+func (s *kvStore) set(key uint64, value []byte) {
+	s.size += int64(len(value) - len(s.values[key]))
+	s.values[key] = value
+}
+
+// Copies the value of key into buffer and returns false if the key is not in the store
+func (s *kvStore) Read(key uint64, buffer []byte) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	value, ok := s.values[key]
+	copy(buffer, value)
+	return ok
+}
+
+// Stores value under key and appends it to the log if persist is set and storage was opened
+func (s *kvStore) Write(key uint64, value []byte, persist bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.set(key, value)
+	if persist && s.log != nil {
+		record := make([]byte, storageRecordHeaderSize+len(value))
+		binary.LittleEndian.PutUint64(record, key)
+		binary.LittleEndian.PutUint32(record[8:], uint32(len(value)))
+		copy(record[storageRecordHeaderSize:], value)
+		if _, err := s.log.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the size of all values in the store
+func (s *kvStore) Size() int64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.size
+}
+
+// Opens the log of persisted writes in directory path and restores the values written to it
+// Without storage, persisted writes are only kept in memory
+func OpenStorage(path string) (int, error) {
+	file, err := os.OpenFile(filepath.Join(path, storageLogName), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, err
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	reader := bufio.NewReader(file)
+	header := make([]byte, storageRecordHeaderSize)
+	offset, records := int64(0), 0
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			break
+		}
+		value := make([]byte, binary.LittleEndian.Uint32(header[8:]))
+		if _, err := io.ReadFull(reader, value); err != nil {
+			break
+		}
+		store.set(binary.LittleEndian.Uint64(header), value)
+		offset += int64(len(header) + len(value))
+		records++
+	}
+
+	// Drop a partially written record at the end, otherwise the records appended after it can't be read
+	if err := file.Truncate(offset); err != nil {
+		file.Close()
+		return 0, err
+	}
+
+	store.log = file
+	return records, nil
+}
+
+// Combines the storage task response in taskResponses with storageTaskResponse
+func ConcatenateStorageResponses(taskResponses *MutexTaskResponses, storageTaskResponse *generated.StorageTaskResponse) {
+	taskResponses.Mutex.Lock()
+	defer taskResponses.Mutex.Unlock()
+
+	if taskResponses.StorageTask != nil {
+		for k, v := range storageTaskResponse.Services {
+			uniqueKey := UniqueKey(taskResponses.StorageTask.Services, k)
+			taskResponses.StorageTask.Services[uniqueKey] = v
+		}
+		taskResponses.StorageTask.Aborted = append(taskResponses.StorageTask.Aborted, storageTaskResponse.Aborted...)
+	} else {
+		taskResponses.StorageTask = storageTaskResponse
+	}
+}
+
+func (s *StorageTask) ExecAllowed(endpoint *model.Endpoint) bool {
+	return endpoint.StorageComplexity != nil
+}
+
+// Reads and writes random keys in the key-value store
+// Returns false if ctx was cancelled or a persisted write failed before all operations completed
+func StressStorage(ctx context.Context, stressParams *model.StorageComplexity) (*generated.StorageStats, bool) {
+	// Zipf distributions are not safe for concurrent use, so every task has its own source
+	rng := rand.New(rand.NewSource(rand.Int63()))
+	nextKey := func() uint64 {
+		return uint64(rng.Intn(stressParams.Keys))
+	}
+	if stressParams.Distribution == "zipf" {
+		nextKey = rand.NewZipf(rng, stressParams.ZipfExponent, 1, uint64(stressParams.Keys-1)).Uint64
+	}
+
+	stats := &generated.StorageStats{}
+	buffer := make([]byte, stressParams.ValueSize)
+	completed := true
+
+	for i := 0; i < stressParams.Operations; i++ {
+		if ctx.Err() != nil {
+			completed = false
+			break
+		}
+
+		key := nextKey()
+		if rng.Float32() < stressParams.ReadRatio {
+			if store.Read(key, buffer) {
+				stats.Hits++
+			} else {
+				stats.Misses++
+			}
+		} else {
+			if err := store.Write(key, randomData(stressParams.ValueSize), stressParams.Persist); err != nil {
+				completed = false
+				break
+			}
+			stats.Writes++
+		}
+	}
+
+	stats.StoredBytes = store.Size()
+	return stats, completed
+}
+
+// Read and write the key-value store of the service, if the endpoint has a defined storage complexity
+func (s *StorageTask) ExecTask(ctx context.Context, endpoint *model.Endpoint, responses *MutexTaskResponses) {
+	stats, completed := StressStorage(ctx, endpoint.StorageComplexity)
+
+	svc := fmt.Sprintf("%s/%s", util.ServiceName, endpoint.Name)
+	storageTaskResponse := &generated.StorageTaskResponse{
+		Services: map[string]*generated.StorageStats{
+			svc: stats,
+		},
+	}
+	if !completed {
+		storageTaskResponse.Aborted = []string{svc}
+	}
+	ConcatenateStorageResponses(responses, storageTaskResponse)
+
+	util.LogStorageTask(endpoint, stats.Hits, stats.Misses, stats.Writes, stats.StoredBytes, !completed)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stressors
+
+import (
+	"application-emulator/src/util"
+	"context"
+	"runtime"
+	"sync/atomic"
+)
+
+// Number of iterations of the work kernel in one work unit
+// This takes a few milliseconds on a modern server CPU
+const workUnitIterations = 1000000
+
+// Number of work units executed to calibrate the CPU at startup
+const calibrationWorkUnits = 50
+
+// Results of the work kernel are stored here so that the compiler can't remove it
+var workSink uint64
+
+// Runs a fixed number of xorshift iterations, which takes the same number of instructions on any CPU
+func workKernel(x uint64, iterations int) uint64 {
+	for i := 0; i < iterations; i++ {
+		x ^= x << 13
+		x ^= x >> 7
+		x ^= x << 17
+	}
+	return x
+}
+
+// Executes workUnits units of work and returns the CPU time it took in seconds
+// Returns false if ctx was cancelled before all work was done
+func StressWork(ctx context.Context, workUnits float32, lockThread bool) (float32, bool) {
+	if lockThread {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+	}
+
+	start := util.ThreadCPUTime()
+	elapsed := func() float32 {
+		return float32(util.ThreadCPUTime()-start) / 1000000000.0
+	}
+
+	wholeUnits := int(workUnits)
+	remainingIterations := int((workUnits - float32(wholeUnits)) * workUnitIterations)
+	done := ctx.Done()
+	x := uint64(start) | 1
+
+	for i := 0; i < wholeUnits; i++ {
+		select {
+		case <-done:
+			return elapsed(), false
+		default:
+		}
+		x = workKernel(x, workUnitIterations)
+	}
+	x = workKernel(x, remainingIterations)
+	atomic.AddUint64(&workSink, x)
+
+	return elapsed(), true
+}
+
+// Measures the CPU time in seconds that one work unit takes on this node
+// The result is only logged, so that results from different nodes can be compared
+// Work units always run workUnitIterations iterations of the work kernel, however long they take on this node
+func CalibrateCPU() float32 {
+	elapsed, _ := StressWork(context.Background(), calibrationWorkUnits, true)
+	return elapsed / calibrationWorkUnits
+}
@@ -41,7 +41,7 @@ type EndpointTrace struct {
 func LogConfiguration(configMap *model.ConfigMap) {
 	// Get the process count from Go to make sure settings were applied
 	processes := runtime.GOMAXPROCS(0)
-	log.Printf("Application emulator started at *:5000, logging: %t, processes: %d", LoggingEnabled, processes)
+	log.Printf("Application emulator started at *:%d, logging: %t, processes: %d", configMap.Port, LoggingEnabled, processes)
 
 	endpoints := []string{}
 	for _, endpoint := range configMap.Endpoints {
@@ -60,6 +60,17 @@ func LogConfiguration(configMap *model.ConfigMap) {
 	}
 }
 
+// Call at start of program to print the CPU calibration to stdout
+// The calibration is informational and doesn't change how much work a work unit does
+func LogCalibration(workUnitTime float32) {
+	log.Printf("CPU calibration: workUnitTime=%s", FormatTime(float64(workUnitTime)))
+}
+
+// Call at start of program to print the restored key-value store to stdout
+func LogStorage(path string, records int) {
+	log.Printf("Storage: path=%s restoredWrites=%d", path, records)
+}
+
 // Call at start of endpoint call to trace execution time
 func TraceEndpointCall(endpoint *model.Endpoint, protocol string) *EndpointTrace {
 	if LoggingEnabled {
@@ -77,42 +88,102 @@ func TraceEndpointCall(endpoint *model.Endpoint, protocol string) *EndpointTrace
 }
 
 // Call at end of endpoint call to print stats to stdout
-func LogEndpointCall(trace *EndpointTrace) {
+// err is the reason the request was aborted or rejected, or nil if it completed
+func LogEndpointCall(trace *EndpointTrace, err error) {
 	if trace != nil {
 		responseTime := time.Now().Sub(trace.Time).Seconds()
 		cpuTime := float64(ProcessCPUTime()-trace.CPUTime) / 1000000000.0
 		responseTimeFmt, cpuTimeFmt := FormatTime(responseTime), FormatTime(cpuTime)
 
-		log.Printf("%s/%s: %s %s responseTime=%s cpuTime=%s",
-			ServiceName, trace.Endpoint.Name, trace.Protocol, trace.Endpoint.ExecutionMode, responseTimeFmt, cpuTimeFmt)
+		if err != nil {
+			log.Printf("%s/%s: %s %s responseTime=%s cpuTime=%s error=%q",
+				ServiceName, trace.Endpoint.Name, trace.Protocol, trace.Endpoint.ExecutionMode, responseTimeFmt, cpuTimeFmt, err)
+		} else {
+			log.Printf("%s/%s: %s %s responseTime=%s cpuTime=%s",
+				ServiceName, trace.Endpoint.Name, trace.Protocol, trace.Endpoint.ExecutionMode, responseTimeFmt, cpuTimeFmt)
+		}
 	}
 }
 
 // Call at end of CPU task to print params to stdout
-func LogCPUTask(endpoint *model.Endpoint) {
+func LogCPUTask(endpoint *model.Endpoint, executionTime float32, aborted bool) {
 	if LoggingEnabled {
-		executionTime := FormatTime(float64(endpoint.CpuComplexity.ExecutionTime))
+		executionTimeFmt := FormatTime(float64(executionTime))
 		threads := endpoint.CpuComplexity.Threads
 
-		log.Printf("%s/%s: CPU task executionTime=%s threads=%d lockThreads=%t",
-			ServiceName, endpoint.Name, executionTime, threads, true)
+		if endpoint.CpuComplexity.Mode == "work" {
+			log.Printf("%s/%s: CPU task workUnits=%g executionTime=%s threads=%d lockThreads=%t aborted=%t",
+				ServiceName, endpoint.Name, endpoint.CpuComplexity.WorkUnits, executionTimeFmt, threads, true, aborted)
+		} else if endpoint.CpuComplexity.Kernel != "" && endpoint.CpuComplexity.Kernel != "busy" {
+			log.Printf("%s/%s: CPU task kernel=%s workingSetSize=%d executionTime=%s threads=%d lockThreads=%t aborted=%t",
+				ServiceName, endpoint.Name, endpoint.CpuComplexity.Kernel, endpoint.CpuComplexity.WorkingSetSize, executionTimeFmt, threads, true, aborted)
+		} else {
+			log.Printf("%s/%s: CPU task executionTime=%s threads=%d lockThreads=%t aborted=%t",
+				ServiceName, endpoint.Name, executionTimeFmt, threads, true, aborted)
+		}
+	}
+}
+
+// Call when a request overrides the behavior of an endpoint to print the overrides to stdout
+func LogOverrides(endpoint *model.Endpoint, overrides fmt.Stringer) {
+	if LoggingEnabled {
+		log.Printf("%s/%s: Overrides %s", ServiceName, endpoint.Name, overrides)
+	}
+}
+
+// Call at end of payload task to print params to stdout
+func LogPayloadTask(endpoint *model.Endpoint, payloadSize int, transformedSize int, aborted bool) {
+	if LoggingEnabled {
+		stressParams := endpoint.PayloadComplexity
+		cpuTimePerKb := FormatTime(float64(stressParams.CpuTimePerKb))
+
+		log.Printf("%s/%s: Payload task contentType=%s payloadSize=%d transform=%s transformedSize=%d hash=%t cpuTimePerKb=%s aborted=%t",
+			ServiceName, endpoint.Name, stressParams.ContentType, payloadSize, stressParams.Transform, transformedSize, stressParams.Hash, cpuTimePerKb, aborted)
+	}
+}
+
+// Call at end of storage task to print params to stdout
+func LogStorageTask(endpoint *model.Endpoint, hits, misses, writes int32, storedBytes int64, aborted bool) {
+	if LoggingEnabled {
+		stressParams := endpoint.StorageComplexity
+
+		log.Printf("%s/%s: Storage task keys=%d valueSize=%d operations=%d distribution=%s hits=%d misses=%d writes=%d storedBytes=%d aborted=%t",
+			ServiceName, endpoint.Name, stressParams.Keys, stressParams.ValueSize, stressParams.Operations, stressParams.Distribution,
+			hits, misses, writes, storedBytes, aborted)
+	}
+}
+
+// Call at end of lock task to print params to stdout
+func LogLockTask(endpoint *model.Endpoint, waitTime float64, aborted bool) {
+	if LoggingEnabled {
+		locks := endpoint.LockComplexity.Locks
+		holdTime := FormatTime(float64(endpoint.LockComplexity.HoldTime))
+		holdMode := endpoint.LockComplexity.HoldMode
+
+		log.Printf("%s/%s: Lock task locks=%d holdTime=%s holdMode=%s waitTime=%s aborted=%t",
+			ServiceName, endpoint.Name, locks, holdTime, holdMode, FormatTime(waitTime), aborted)
 	}
 }
 
 // Call at end of network task to print params to stdout
-func LogNetworkTask(endpoint *model.Endpoint, responses []generated.EndpointResponse) {
+func LogNetworkTask(endpoint *model.Endpoint, responses []generated.EndpointResponse, aborted bool) {
 	if LoggingEnabled {
 		executionMode := endpoint.NetworkComplexity.ForwardRequests
 		payloadSize := endpoint.NetworkComplexity.ResponsePayloadSize
 		calledServices := len(endpoint.NetworkComplexity.CalledServices)
 
 		statuses := make([]string, 0, len(responses))
+		hedges, hedgeWins := 0, 0
 		for _, response := range responses {
 			statuses = append(statuses, fmt.Sprintf("%s/%s:%s", response.Protocol, response.Service.Endpoint, response.Status))
+			hedges += response.Hedges
+			if response.HedgeWon {
+				hedgeWins++
+			}
 		}
 		formattedStatuses := fmt.Sprint(statuses)
 
-		log.Printf("%s/%s: Network task %s payloadSize=%d calledServices=%d statuses=%s",
-			ServiceName, endpoint.Name, executionMode, payloadSize, calledServices, formattedStatuses)
+		log.Printf("%s/%s: Network task %s payloadSize=%d calledServices=%d hedges=%d hedgeWins=%d aborted=%t statuses=%s",
+			ServiceName, endpoint.Name, executionMode, payloadSize, calledServices, hedges, hedgeWins, aborted, formattedStatuses)
 	}
 }
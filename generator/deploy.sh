@@ -44,9 +44,41 @@ else
     echo "\t or"
     echo "\t http://$NODE_IP:30000"
   fi
+  # Resources deployed before clusters had their own label are only labeled version=<cluster>
+  # Their workloads have immutable selectors and are replaced, and the rest is pruned once the new resources are applied
+  delete_legacy() {
+    kubectl delete $1 --all-namespaces -l "version=$2,!cluster" --context $2 --ignore-not-found
+  }
   # Deploy the microservices to clusters
-  for d in ./k8s/*; do
-    echo "Applying deployment manifests to ${d##./k8s/}"
-    [[ -d "$d" ]] && kubectl apply --prune -f k8s/${d##./k8s/} -l version=${d##./k8s/} --context ${d##./k8s/}
-  done
+  if [[ -d helm ]]; then
+    # Generated with --helm, every cluster gets a release with its values file
+    for values in ./helm/values-*.yaml; do
+      cluster=$(basename $values .yaml)
+      cluster=${cluster#values-}
+      [[ -f ./helm/namespaces-${cluster}.yaml ]] && kubectl apply -f ./helm/namespaces-${cluster}.yaml --context ${cluster}
+      echo "Installing Helm release to ${cluster}"
+      delete_legacy deployments,statefulsets,jobs ${cluster}
+      helm upgrade --install hydragen ./helm -f $values --kube-context ${cluster}
+    done
+  elif [[ -d kustomize ]]; then
+    # Generated with --kustomize, every cluster has an overlay
+    for d in ./kustomize/overlays/*; do
+      cluster=${d##./kustomize/overlays/}
+      [[ -f $d/namespaces.yaml ]] && kubectl apply -f $d/namespaces.yaml --context ${cluster}
+      echo "Applying Kustomize overlay to ${cluster}"
+      delete_legacy deployments,statefulsets,jobs ${cluster}
+      kubectl apply --prune -k $d -l cluster=${cluster} --context ${cluster}
+      delete_legacy configmaps,services,ingresses ${cluster}
+    done
+  else
+    for d in ./k8s/*; do
+      # Namespaces are not labeled with the cluster, so they are applied without pruning
+      [[ -f $d/namespaces.yaml ]] && kubectl apply -f $d/namespaces.yaml --context ${d##./k8s/}
+      echo "Applying deployment manifests to ${d##./k8s/}"
+      [[ -d "$d" ]] || continue
+      delete_legacy deployments,statefulsets,jobs ${d##./k8s/}
+      kubectl apply --prune -f k8s/${d##./k8s/} -l cluster=${d##./k8s/} --context ${d##./k8s/}
+      delete_legacy configmaps,services,ingresses ${d##./k8s/}
+    done
+  fi
 fi
@@ -35,6 +35,21 @@ if [[ -d k8s ]]; then
 	rm -r k8s
 fi
 
+if [[ -d kustomize ]]; then
+	echo "Deleting previous Kustomize files"
+	rm -r kustomize
+fi
+
+if [[ -d helm ]]; then
+	echo "Deleting previous Helm chart"
+	rm -r helm
+fi
+
+if [[ -d compose ]]; then
+	echo "Deleting previous Docker Compose files"
+	rm -r compose
+fi
+
 images="$(docker images $(hostname -f)/hydragen-emulator -q)"
 
 if [[ ! -z "$images" ]]; then
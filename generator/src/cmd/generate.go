@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"application-generator/src/pkg/generate"
+	s "application-generator/src/pkg/service"
 	model "application-model"
 
 	"bufio"
@@ -116,6 +117,11 @@ func getPrefix(s string) string {
 	return prefix
 }
 
+var generateHelm, generateKustomize bool
+var generateV2 []string
+var generateV2Factor float64
+var generateV2Weight int
+
 var generateCmd = &cobra.Command{
 	Use:   "generate [mode] [input-file]",
 	Short: "This command can be run under two different modes: (i) 'random' mode which generates a random description file or (ii) 'preset' mode which generates Kubernetes manifest based on a description file in the input directory",
@@ -162,13 +168,32 @@ var generateCmd = &cobra.Command{
 		}
 
 		config, clusters, buildHash := generate.Parse(inputFile)
+		var err error
+		if len(generateV2) > 0 {
+			config, err = generate.CreateV2(config, generateV2, generateV2Factor, generateV2Weight)
+			exitIfError(err)
+		}
 
 		generate.CreateGrpcEndpoints(config)
-		generate.CreateK8sYaml(config, clusters, buildHash)
+		if generateHelm {
+			err = generate.CreateHelmChart(config, clusters, buildHash)
+		} else if generateKustomize {
+			err = generate.CreateKustomize(config, clusters, buildHash)
+		} else {
+			err = generate.CreateK8sYaml(config, clusters, buildHash)
+		}
+		exitIfError(err)
+		generate.CreateComposeYaml(config, buildHash)
 		generate.CreateDockerImage(config, buildHash)
 	},
 }
 
 func init() {
+	generateCmd.Flags().BoolVar(&generateHelm, "helm", false, "Write a Helm chart with values files for every cluster instead of the Kubernetes manifests")
+	generateCmd.Flags().BoolVar(&generateKustomize, "kustomize", false, "Write a Kustomize base and an overlay for every cluster instead of the Kubernetes manifests")
+	generateCmd.MarkFlagsMutuallyExclusive("helm", "kustomize")
+	generateCmd.Flags().StringSliceVar(&generateV2, "v2", nil, "Deploy a second version of the given services next to the first one for canary or blue-green rollouts")
+	generateCmd.Flags().Float64Var(&generateV2Factor, "v2-factor", s.RolloutV2FactorDefault, "How many times as long the stressors of the second versions take")
+	generateCmd.Flags().IntVar(&generateV2Weight, "v2-weight", 0, "Percentage of the requests to the given services that the second versions get, the first versions get the rest")
 	rootCmd.AddCommand(generateCmd)
 }
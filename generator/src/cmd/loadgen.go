@@ -0,0 +1,150 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"application-generator/src/pkg/generate"
+	"application-generator/src/pkg/loadgen"
+
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var loadgenFlags struct {
+	endpoints    []string
+	addresses    map[string]string
+	pattern      string
+	duration     float64
+	users        int
+	thinkTime    float64
+	rate         float64
+	endRate      float64
+	stepRate     float64
+	stepDuration float64
+	trace        string
+	maxInFlight  int
+	timeout      float64
+	payloadSize  int
+	headers      map[string]string
+	interval     float64
+	output       string
+	format       string
+	workload     bool
+	repeat       bool
+}
+
+var loadgenCmd = &cobra.Command{
+	Use:   "loadgen [input-file]",
+	Short: "Sends requests to entry endpoints of the application described by an input file and reports latency percentiles, throughput, error rates and the combined task responses",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, _, err := generate.Load(args[0])
+		exitIfError(err)
+
+		var options *loadgen.Options
+		if loadgenFlags.workload {
+			options, err = loadgen.WorkloadOptions(&config, loadgenFlags.addresses)
+			exitIfError(err)
+			if !cmd.Flags().Changed("format") {
+				loadgenFlags.format = config.Workload.Format
+			}
+		} else {
+			endpoints, err := loadgen.ParseEndpoints(loadgenFlags.endpoints)
+			exitIfError(err)
+			targets, err := loadgen.Targets(&config, endpoints, "", loadgenFlags.addresses)
+			exitIfError(err)
+
+			options = &loadgen.Options{
+				Targets:      targets,
+				Pattern:      loadgenFlags.pattern,
+				Duration:     loadgen.Seconds(loadgenFlags.duration),
+				Users:        loadgenFlags.users,
+				ThinkTime:    loadgen.Seconds(loadgenFlags.thinkTime),
+				Rate:         loadgenFlags.rate,
+				EndRate:      loadgenFlags.endRate,
+				StepRate:     loadgenFlags.stepRate,
+				StepDuration: loadgen.Seconds(loadgenFlags.stepDuration),
+				MaxInFlight:  loadgenFlags.maxInFlight,
+				Timeout:      loadgen.Seconds(loadgenFlags.timeout),
+				PayloadSize:  loadgenFlags.payloadSize,
+				Headers:      loadgenFlags.headers,
+				Interval:     loadgen.Seconds(loadgenFlags.interval),
+			}
+		}
+		if loadgenFlags.trace != "" {
+			options.Trace, err = loadgen.ReadTrace(loadgenFlags.trace)
+			exitIfError(err)
+		}
+		if loadgenFlags.format != "csv" && loadgenFlags.format != "json" {
+			exitIfError(fmt.Errorf("unknown output format '%s'", loadgenFlags.format))
+		}
+
+		output := os.Stdout
+		if loadgenFlags.output != "" {
+			output, err = os.Create(loadgenFlags.output)
+			exitIfError(err)
+			defer output.Close()
+		}
+
+		// The statistics of every repetition are written after it finished
+		for {
+			report, err := loadgen.Run(options, os.Stderr)
+			exitIfError(err)
+			report.WriteSummary(os.Stderr)
+
+			if loadgenFlags.format == "json" {
+				err = report.WriteJSON(output)
+			} else {
+				err = report.WriteCSV(output)
+			}
+			exitIfError(err)
+
+			if !loadgenFlags.repeat {
+				break
+			}
+		}
+	},
+}
+
+func init() {
+	flags := loadgenCmd.Flags()
+	flags.StringSliceVarP(&loadgenFlags.endpoints, "endpoint", "e", nil, "Entry endpoint to send requests to as service/endpoint, optionally followed by :weight, can be repeated")
+	flags.StringToStringVar(&loadgenFlags.addresses, "address", nil, "Address (host:port) to send requests for a service to instead of the Kubernetes service, as service=host:port")
+	flags.StringVarP(&loadgenFlags.pattern, "pattern", "p", "closed", "Arrival pattern: closed, constant, poisson, step, ramp or trace")
+	flags.Float64VarP(&loadgenFlags.duration, "duration", "d", 60, "Duration of the test in seconds")
+	flags.IntVarP(&loadgenFlags.users, "users", "u", 1, "Number of users in the closed pattern")
+	flags.Float64Var(&loadgenFlags.thinkTime, "think-time", 0, "Seconds a user in the closed pattern waits after every response")
+	flags.Float64VarP(&loadgenFlags.rate, "rate", "r", 10, "Requests per second of open patterns, or the initial rate of the step and ramp patterns")
+	flags.Float64Var(&loadgenFlags.endRate, "end-rate", 0, "Requests per second at the end of the ramp pattern")
+	flags.Float64Var(&loadgenFlags.stepRate, "step-rate", 0, "Requests per second added at every step of the step pattern")
+	flags.Float64Var(&loadgenFlags.stepDuration, "step-duration", 10, "Seconds between steps of the step pattern")
+	flags.StringVar(&loadgenFlags.trace, "trace", "", "File with the arrival time in seconds of one request per line, for the trace pattern")
+	flags.IntVar(&loadgenFlags.maxInFlight, "max-in-flight", 1000, "Maximum number of requests in flight, open-loop arrivals above this are dropped")
+	flags.Float64Var(&loadgenFlags.timeout, "timeout", 10, "Timeout of every request in seconds")
+	flags.IntVar(&loadgenFlags.payloadSize, "payload-size", 0, "Number of characters in the payload of every request")
+	flags.StringToStringVarP(&loadgenFlags.headers, "header", "H", nil, "HTTP header or gRPC metadata sent with every request, as key=value")
+	flags.Float64VarP(&loadgenFlags.interval, "interval", "i", 1, "Length in seconds of the intervals statistics are reported for")
+	flags.StringVarP(&loadgenFlags.output, "output", "o", "", "File to write the statistics to (default stdout)")
+	flags.StringVarP(&loadgenFlags.format, "format", "f", "csv", "Format of the statistics: csv (every interval) or json (every interval, summary and task responses)")
+
+	flags.BoolVarP(&loadgenFlags.workload, "workload", "w", false, "Use the endpoints and arrival pattern of the workload section in the input file instead of the flags")
+	flags.BoolVar(&loadgenFlags.repeat, "repeat", false, "Start the test again whenever it finishes, until the load generator is stopped")
+
+	rootCmd.AddCommand(loadgenCmd)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"application-generator/src/pkg/generate"
+	"application-generator/src/pkg/loadgen"
+	"application-generator/src/pkg/local"
+
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+var runFlags struct {
+	emulator     string
+	basePort     int
+	dir          string
+	startTimeout float64
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run [input-file]",
+	Short: "Runs every service of the application described by an input file on localhost, as child processes of the application emulator, until interrupted",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, _, err := generate.Load(args[0])
+		exitIfError(err)
+
+		options := &local.Options{
+			Emulator:     runFlags.emulator,
+			BasePort:     runFlags.basePort,
+			Dir:          runFlags.dir,
+			StartTimeout: loadgen.Seconds(runFlags.startTimeout),
+		}
+		if options.Dir == "" {
+			options.Dir, err = os.MkdirTemp("", "hydragen-")
+		} else {
+			err = os.MkdirAll(options.Dir, 0777)
+		}
+		exitIfError(err)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		err = local.Run(ctx, &config, options, os.Stdout)
+		// Config files and storage are only kept if a directory was given
+		if runFlags.dir == "" {
+			os.RemoveAll(options.Dir)
+		}
+		exitIfError(err)
+	},
+}
+
+func init() {
+	flags := runCmd.Flags()
+	flags.StringVar(&runFlags.emulator, "emulator", "app-emulator", "Path of the application emulator binary, which must include the generated code of gRPC services")
+	flags.IntVar(&runFlags.basePort, "base-port", 5000, "Port of the first service, the following services listen on the next ports")
+	flags.StringVar(&runFlags.dir, "dir", "", "Directory to keep the config files and persistent storage of the services in (default a temporary directory)")
+	flags.Float64Var(&runFlags.startTimeout, "start-timeout", 30, "Seconds to wait for all services to accept connections")
+
+	rootCmd.AddCommand(runCmd)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	s "application-generator/src/pkg/service"
+	model "application-model"
+)
+
+// Returns the autoscalers of a service on a cluster
+// Horizontal scaling uses a HorizontalPodAutoscaler or a KEDA ScaledObject, vertical scaling a VerticalPodAutoscaler
+// Every version of a service is scaled on its own, except for versions with a fixed number of replicas which are not scaled horizontally
+func autoscalingManifests(service model.Service, cluster model.Cluster) []interface{} {
+	targetKind := "Deployment"
+	if service.Storage != nil {
+		targetKind = "StatefulSet"
+	}
+
+	manifests := []interface{}{}
+	for _, version := range serviceVersions(service) {
+		name := deploymentName(service, version)
+		if autoscaling := cluster.Autoscaling; autoscaling != nil && version.Replicas == 0 {
+			if autoscaling.Kind == "keda" {
+				manifests = append(manifests, s.CreateScaledObject(name, cluster.Cluster, cluster.Namespace, targetKind, name,
+					autoscaling.MinReplicas, autoscaling.MaxReplicas, autoscaling.Cpu, autoscaling.Memory, autoscaling.Metric, autoscaling.PrometheusAddress))
+			} else {
+				manifests = append(manifests, s.CreateHorizontalPodAutoscaler(name, cluster.Cluster, cluster.Namespace, targetKind, name,
+					autoscaling.MinReplicas, autoscaling.MaxReplicas, autoscaling.Cpu, autoscaling.Memory, autoscaling.Metric))
+			}
+		}
+
+		if verticalScaling := service.VerticalScaling; verticalScaling != nil {
+			manifests = append(manifests, s.CreateVerticalPodAutoscaler(name, cluster.Cluster, cluster.Namespace, targetKind, name,
+				s.ContainerName, verticalScaling.Mode, verticalScaling.MinCpu, verticalScaling.MinMemory, verticalScaling.MaxCpu,
+				verticalScaling.MaxMemory, verticalScaling.ControlledValues))
+		}
+	}
+
+	return manifests
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	s "application-generator/src/pkg/service"
+	model "application-model"
+	"fmt"
+)
+
+// Returns the names of the services called by a service, in the order of their first call
+func calledServices(service model.Service) []string {
+	called := []string{}
+	for i := range service.Endpoints {
+		for _, networkComplexity := range networkComplexities(&service.Endpoints[i]) {
+			for _, calledService := range networkComplexity.CalledServices {
+				called = append(called, calledService.Service)
+			}
+		}
+	}
+	return Unique(called)
+}
+
+// Returns the DNS name of a service as seen from a namespace on a cluster, or an empty string if it is reached through its name
+// Services that are not in the input are assumed to be reachable through their name unless they have a discovery address
+func discoveryHost(config model.FileConfig, name, c_id, namespace string) (string, error) {
+	var target *model.Service
+	for i := range config.Services {
+		if config.Services[i].Name == name {
+			target = &config.Services[i]
+		}
+	}
+
+	if target != nil {
+		if targetNamespace, ok := clusterNamespaces(config, c_id)[name]; ok {
+			if targetNamespace == namespace {
+				return "", nil
+			}
+			return fmt.Sprintf("%s.%s.svc.cluster.local", name, targetNamespace), nil
+		}
+	}
+
+	discovery := config.Settings.Discovery
+	if discovery != nil {
+		if address, ok := discovery.Addresses[name]; ok {
+			return address, nil
+		}
+		if discovery.Method == s.DiscoveryMcs && target != nil && len(target.Clusters) > 0 {
+			// Services exported from different namespaces are different services of the cluster set, the first one is used
+			return fmt.Sprintf("%s.%s.%s", name, target.Clusters[0].Namespace, s.DiscoveryMcsDomain), nil
+		}
+	}
+
+	if target == nil {
+		return "", nil
+	}
+	return "", fmt.Errorf("it is not deployed on the cluster and has no discovery address")
+}
+
+// Returns the first service in a namespace on a cluster that calls a service
+func firstCaller(config model.FileConfig, name, c_id, namespace string) string {
+	for _, service := range config.Services {
+		for _, cluster := range service.Clusters {
+			if cluster.Cluster != c_id || cluster.Namespace != namespace {
+				continue
+			}
+			for _, called := range calledServices(service) {
+				if called == name {
+					return service.Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// Returns the ServiceExport of a service on a cluster and the ExternalName Services of the called services that are not deployed in its namespace
+// Every called service gets a single ExternalName Service in a namespace, which belongs to the first service in the namespace calling it
+// Fails if a called service can't be reached from the cluster, which validation rejects beforehand
+func discoveryManifests(config model.FileConfig, service model.Service, cluster model.Cluster) ([]interface{}, error) {
+	manifests := []interface{}{}
+
+	discovery := config.Settings.Discovery
+	if discovery != nil && discovery.Method == s.DiscoveryMcs {
+		manifests = append(manifests, s.CreateServiceExport(service.Name, cluster.Cluster, cluster.Namespace))
+	}
+
+	for _, name := range calledServices(service) {
+		host, err := discoveryHost(config, name, cluster.Cluster, cluster.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("service '%s' on cluster '%s' can't reach service '%s': %s", service.Name, cluster.Cluster, name, err)
+		}
+		if host == "" || firstCaller(config, name, cluster.Cluster, cluster.Namespace) != service.Name {
+			continue
+		}
+		manifests = append(manifests, s.CreateExternalNameService(name, cluster.Cluster, cluster.Namespace, host))
+	}
+
+	return manifests, nil
+}
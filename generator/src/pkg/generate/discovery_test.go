@@ -0,0 +1,145 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	s "application-generator/src/pkg/service"
+	model "application-model"
+
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// Returns the kinds and names of manifests
+func manifestNames(manifests []interface{}) []string {
+	names := []string{}
+	for _, manifest := range manifests {
+		values := yamlMap(manifest)
+		metadata := values["metadata"].(map[string]interface{})
+		names = append(names, fmt.Sprintf("%s %s", values["kind"], metadata["name"]))
+	}
+	return names
+}
+
+// Moves backend of the test description to a cluster or namespace
+func moveBackend(c_id, namespace string) func(config *model.FileConfig) {
+	return func(config *model.FileConfig) {
+		config.Services[1].Clusters = []model.Cluster{{Cluster: c_id, Namespace: namespace}}
+	}
+}
+
+func TestDiscoveryHost(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(config *model.FileConfig)
+		discovery *model.Discovery
+		service   string
+		host      string
+		err       bool
+	}{
+		{"same namespace", nil, nil, "backend", "", false},
+		{"other namespace", moveBackend("cluster1", "backend"), nil, "backend", "backend.backend.svc.cluster.local", false},
+		{"other namespace with discovery", moveBackend("cluster1", "backend"), &model.Discovery{Method: s.DiscoveryMcs}, "backend",
+			"backend.backend.svc.cluster.local", false},
+		{"MCS", moveBackend("cluster2", "backend"), &model.Discovery{Method: s.DiscoveryMcs}, "backend", "backend.backend.svc.clusterset.local", false},
+		{"external address", moveBackend("cluster2", "default"),
+			&model.Discovery{Method: s.DiscoveryExternal, Addresses: map[string]string{"backend": "backend.example.com"}}, "backend", "backend.example.com", false},
+		{"external address with MCS", moveBackend("cluster2", "default"),
+			&model.Discovery{Method: s.DiscoveryMcs, Addresses: map[string]string{"backend": "backend.example.com"}}, "backend", "backend.example.com", false},
+		{"external without address", moveBackend("cluster2", "default"), &model.Discovery{Method: s.DiscoveryExternal}, "backend", "", true},
+		{"other cluster without discovery", moveBackend("cluster2", "default"), nil, "backend", "", true},
+		{"service not in the input", nil, nil, "database", "", false},
+		{"service not in the input with address", nil,
+			&model.Discovery{Method: s.DiscoveryExternal, Addresses: map[string]string{"database": "database.example.com"}}, "database", "database.example.com", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Validation rejects unreachable services with the error of discoveryHost, which is checked here
+			config, _ := loadTestDescription(t, test.modify)
+			config.Settings.Discovery = test.discovery
+
+			host, err := discoveryHost(config, test.service, "cluster1", "default")
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %t, got: %v", test.err, err)
+			}
+			if host != test.host {
+				t.Fatalf("expected host '%s', got '%s'", test.host, host)
+			}
+		})
+	}
+}
+
+func TestDiscoveryManifests(t *testing.T) {
+	// A second caller of backend next to frontend
+	addCaller := func(config *model.FileConfig) {
+		caller := config.Services[0]
+		caller.Name = "frontend2"
+		caller.Endpoints = copyEndpoints(caller.Endpoints)
+		config.Services = append(config.Services, caller)
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(config *model.FileConfig)
+		discovery *model.Discovery
+		service   string
+		manifests []string
+		err       string
+	}{
+		{"same namespace", nil, nil, "frontend", []string{}, ""},
+		{"other namespace", moveBackend("cluster1", "backend"), nil, "frontend", []string{"Service backend"}, ""},
+		{"MCS", moveBackend("cluster2", "default"), &model.Discovery{Method: s.DiscoveryMcs}, "frontend",
+			[]string{"ServiceExport frontend", "Service backend"}, ""},
+		{"MCS for called service", moveBackend("cluster2", "default"), &model.Discovery{Method: s.DiscoveryMcs}, "backend",
+			[]string{"ServiceExport backend"}, ""},
+		{"second caller", func(config *model.FileConfig) {
+			moveBackend("cluster1", "backend")(config)
+			addCaller(config)
+		}, nil, "frontend2", []string{}, ""},
+		{"unreachable", moveBackend("cluster2", "default"), nil, "frontend", nil,
+			"service 'frontend' on cluster 'cluster1' can't reach service 'backend': it is not deployed on the cluster and has no discovery address"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, _ := loadTestDescription(t, test.modify)
+			config.Settings.Discovery = test.discovery
+
+			var service model.Service
+			for _, candidate := range config.Services {
+				if candidate.Name == test.service {
+					service = candidate
+				}
+			}
+			manifests, err := discoveryManifests(config, service, service.Clusters[0])
+			if test.err != "" {
+				if err == nil || err.Error() != test.err {
+					t.Fatalf("expected error '%s', got: %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if names := manifestNames(manifests); !reflect.DeepEqual(names, test.manifests) {
+				t.Fatalf("expected manifests %v, got %v", test.manifests, names)
+			}
+		})
+	}
+}
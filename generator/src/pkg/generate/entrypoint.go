@@ -0,0 +1,91 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	s "application-generator/src/pkg/service"
+	model "application-model"
+
+	"github.com/iancoleman/strcase"
+)
+
+// Returns the names of the entry point endpoints of a service
+func entryPoints(service model.Service) []string {
+	names := []string{}
+	for _, endpoint := range service.Endpoints {
+		if endpoint.EntryPoint {
+			names = append(names, endpoint.Name)
+		}
+	}
+	return names
+}
+
+// Returns the type of the Kubernetes Service of a service, which is ClusterIP unless the service is exposed through it
+func serviceType(config model.FileConfig, service model.Service) string {
+	if config.EntryPoints == nil || len(entryPoints(service)) == 0 {
+		return ""
+	}
+	switch config.EntryPoints.Kind {
+	case "nodeport":
+		return "NodePort"
+	case "loadbalancer":
+		return "LoadBalancer"
+	}
+	return ""
+}
+
+// Returns the Ingress or Gateway API route of the entry point endpoints of a service on a cluster
+// Gateway API routes split the requests between the versions of the service by weight, while Ingresses send them to all pods
+// Services exposed through a NodePort or LoadBalancer Service don't need further manifests
+func entryPointManifests(config model.FileConfig, service model.Service, cluster model.Cluster) []interface{} {
+	entryPoints := entryPoints(service)
+	if config.EntryPoints == nil || len(entryPoints) == 0 {
+		return nil
+	}
+
+	host := config.EntryPoints.Host
+	paths := []string{}
+	for _, name := range entryPoints {
+		paths = append(paths, "/"+name)
+	}
+
+	switch config.EntryPoints.Kind {
+	case "ingress":
+		return []interface{}{
+			s.CreateIngress(service.Name, cluster.Cluster, cluster.Namespace, config.EntryPoints.Class, host, service.Name, s.DefaultExtPort, paths),
+		}
+	case "gateway":
+		gateway := config.EntryPoints.Gateway
+		gatewayNamespace := config.EntryPoints.GatewayNamespace
+		if service.Protocol != "grpc" {
+			return []interface{}{
+				s.CreateHTTPRoute(service.Name, cluster.Cluster, cluster.Namespace, gateway, gatewayNamespace, host, versionBackendRefs(service), paths),
+			}
+		}
+
+		// gRPC methods are named after the service and endpoint in the generated package
+		methods := []string{}
+		for _, name := range entryPoints {
+			methods = append(methods, strcase.ToCamel(name))
+		}
+		return []interface{}{
+			s.CreateGRPCRoute(service.Name, cluster.Cluster, cluster.Namespace, gateway, gatewayNamespace, host, versionBackendRefs(service),
+				s.GrpcPackage+"."+strcase.ToCamel(service.Name), methods),
+		}
+	}
+	return nil
+}
@@ -28,6 +28,7 @@ import (
 	"math/rand"
 	"os"
 	"os/exec"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
@@ -53,27 +54,36 @@ func Unique(strSlice []string) []string {
 	return list
 }
 
-// Parse microservice config file, and return a config struct
-func Parse(configFilename string) (model.FileConfig, []string, string) {
+// Load microservice config file, apply default values and validate it
+// Returns the config struct and the contents of the file
+func Load(configFilename string) (model.FileConfig, []byte, error) {
+	loaded_config := s.CreateFileConfig()
+
 	configFile, err := os.Open(configFilename)
 	if err != nil {
-		panic(err)
+		return loaded_config, nil, err
 	}
+	defer configFile.Close()
 
 	configFileByteValue, _ := io.ReadAll(configFile)
-	loaded_config := s.CreateFileConfig()
 
 	decoder := json.NewDecoder(bytes.NewReader(configFileByteValue))
-	// Panic if input contains unknown fields
+	// Fail if input contains unknown fields
 	decoder.DisallowUnknownFields()
 
 	err = decoder.Decode(&loaded_config)
 	if err != nil {
-		panic(err)
+		return loaded_config, nil, err
 	}
 
 	ApplyDefaults(&loaded_config)
 	err = ValidateFileConfig(&loaded_config)
+	return loaded_config, configFileByteValue, err
+}
+
+// Parse microservice config file, and return a config struct
+func Parse(configFilename string) (model.FileConfig, []string, string) {
+	loaded_config, configFileByteValue, err := Load(configFilename)
 	if err != nil {
 		panic(err)
 	}
@@ -167,7 +177,122 @@ func CreateGrpcEndpoints(config model.FileConfig) {
 	}
 }
 
-func CreateK8sYaml(config model.FileConfig, clusters []string, buildHash string) {
+// Returns the YAML documents of manifests as one file
+func joinManifests(manifests []interface{}) string {
+	yamlDocs := make([]string, 0, len(manifests))
+	for _, manifest := range manifests {
+		yamlDoc, err := yaml.Marshal(manifest)
+		if err != nil {
+			panic(err)
+		}
+		yamlDocs = append(yamlDocs, string(yamlDoc))
+	}
+	return strings.Join(yamlDocs, "---\n")
+}
+
+// Returns the namespace of every service deployed on a cluster
+func clusterNamespaces(config model.FileConfig, c_id string) map[string]string {
+	namespaces := map[string]string{}
+	for _, service := range config.Services {
+		for _, cluster := range service.Clusters {
+			if cluster.Cluster == c_id {
+				namespaces[service.Name] = cluster.Namespace
+			}
+		}
+	}
+	return namespaces
+}
+
+// Returns the ConfigMap and Deployment of a version of a service on a cluster
+func workloadManifests(config model.FileConfig, service model.Service, version model.Version, cluster model.Cluster, buildHash string) []interface{} {
+	name := deploymentName(service, version)
+
+	serv := service.Name
+	protocol := service.Protocol
+	resources := service.Resources
+
+	storage := service.Storage
+	storagePath := ""
+	if storage != nil {
+		storagePath = s.StorageVolumePath
+	}
+
+	cm_data := s.CreateConfigMap(service.Processes, config.Settings.Logging, config.Settings.DeadlineReduction, resources.Limits.Cpu, storagePath,
+		service.DisableOverrides, protocol, version.Endpoints, service.Health)
+
+	serv_json, err := json.Marshal(cm_data)
+	if err != nil {
+		panic(err)
+	}
+
+	c_id := cluster.Cluster
+	namespace := cluster.Namespace
+
+	configmap := s.CreateConfig("config-"+name, "config-"+name, c_id, namespace, string(serv_json))
+
+	image := fmt.Sprintf("%s/%s:%s", s.HostnameFQDN(), s.ImageName, buildHash)
+	deployment := s.CreateDeployment(name, serv, c_id, versionReplicas(version, cluster), deploymentStrategy(service), serv, c_id, version.Name, namespace,
+		s.DefaultPort, s.ContainerName, image, s.ImagePullPolicy, s.VolumePath, s.VolumeName, "config-"+name,
+		s.CreateProbes(s.DefaultPort, protocol, *service.Probes), resources.Requests.Cpu, resources.Requests.Memory,
+		resources.Limits.Cpu, resources.Limits.Memory, cluster.Node, cluster.Annotations, s.CreateScheduling(serv, cluster, clusterNamespaces(config, c_id)))
+	if storage != nil {
+		deployment = s.CreateStatefulSet(deployment, serv+s.HeadlessServiceSuffix, s.StorageVolumeName, s.StorageVolumePath, storage.Size, storage.StorageClass)
+	}
+
+	return []interface{}{configmap, deployment}
+}
+
+// Returns the manifests of a service deployed on a cluster
+// The namespace, cluster label, replicas, node, scheduling constraints and annotations are left out if they are not set in cluster
+func serviceManifests(config model.FileConfig, service model.Service, cluster model.Cluster, buildHash string) []interface{} {
+	serv := service.Name
+	protocol := service.Protocol
+	manifests := []interface{}{}
+
+	// The versions of a service run next to each other and are reached through the same Kubernetes service
+	for _, version := range serviceVersions(service) {
+		manifests = append(manifests, workloadManifests(config, service, version, cluster, buildHash)...)
+	}
+
+	ports := []model.ServicePortInstance{
+		{
+			Name:       protocol,
+			Port:       s.DefaultExtPort,
+			TargetPort: s.DefaultPort,
+		},
+	}
+
+	k8sService := s.CreateService(serv, serv, "", protocol, s.Uri, cluster.Cluster, cluster.Namespace, serviceType(config, service), ports)
+	manifests = append(manifests, k8sService)
+	// StatefulSets need a headless Service for the DNS names of their pods
+	if service.Storage != nil {
+		manifests = append(manifests, s.CreateHeadlessService(serv+s.HeadlessServiceSuffix, serv, protocol, s.Uri, cluster.Cluster,
+			cluster.Namespace, ports))
+	}
+
+	return manifests
+}
+
+// Returns the manifests of a service on a cluster besides its ConfigMap, Deployment and Service
+// Unlike the manifests of serviceManifests, they always belong to a cluster and namespace
+func clusterManifests(config model.FileConfig, service model.Service, cluster model.Cluster) ([]interface{}, error) {
+	discovery, err := discoveryManifests(config, service, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := []interface{}{}
+	manifests = append(manifests, autoscalingManifests(service, cluster)...)
+	manifests = append(manifests, disruptionBudgetManifests(service, cluster)...)
+	manifests = append(manifests, versionManifests(config, service, cluster)...)
+	manifests = append(manifests, meshManifests(config, service, cluster)...)
+	manifests = append(manifests, discovery...)
+	manifests = append(manifests, networkPolicyManifests(config, service, cluster)...)
+	manifests = append(manifests, entryPointManifests(config, service, cluster)...)
+	return manifests, nil
+}
+
+func CreateK8sYaml(config model.FileConfig, clusters []string, buildHash string) error {
 	path, _ := os.Getwd()
 	path = path + "/k8s"
 
@@ -177,71 +302,182 @@ func CreateK8sYaml(config model.FileConfig, clusters []string, buildHash string)
 	}
 
 	for i := 0; i < len(config.Services); i++ {
-		serv := config.Services[i].Name
-		protocol := config.Services[i].Protocol
-		readinessProbe := config.Services[i].ReadinessProbe
+		for j := 0; j < len(config.Services[i].Clusters); j++ {
+			cluster := config.Services[i].Clusters[j]
+			manifestFilePath := fmt.Sprintf(path+"/%s/%s.yaml", cluster.Cluster, config.Services[i].Name)
 
-		resources := config.Services[i].Resources
-		processes := config.Services[i].Processes
+			manifests := serviceManifests(config, config.Services[i], cluster, buildHash)
+			others, err := clusterManifests(config, config.Services[i], cluster)
+			if err != nil {
+				return err
+			}
+			manifests = append(manifests, others...)
+			err = os.WriteFile(manifestFilePath, []byte(joinManifests(manifests)), 0644)
+			if err != nil {
+				return err
+			}
+		}
+	}
 
-		logging := config.Settings.Logging
+	for _, c_id := range Unique(clusters) {
+		if namespaces := namespaceManifests(config, c_id); len(namespaces) > 0 {
+			writeFile(fmt.Sprintf("%s/%s/%s", path, c_id, s.NamespacesFileName), joinManifests(namespaces))
+		}
+	}
+
+	if config.Workload != nil {
+		manifestFilePath := fmt.Sprintf(path+"/%s/%s.yaml", config.Workload.Cluster, s.LoadGeneratorName)
+		return os.WriteFile(manifestFilePath, []byte(joinManifests(loadGeneratorManifests(config, buildHash))), 0644)
+	}
+	return nil
+}
+
+// Returns the manifests of the load generator described by the workload section
+// The load generator reads the input file from its ConfigMap
+func loadGeneratorManifests(config model.FileConfig, buildHash string) []interface{} {
+	workload := config.Workload
+
+	config_json, err := json.Marshal(config)
+	if err != nil {
+		panic(err)
+	}
+
+	configMapName := "config-" + s.LoadGeneratorName
+	configmap := s.CreateConfig(configMapName, configMapName, workload.Cluster, workload.Namespace, string(config_json))
+
+	args := []string{"loadgen", s.VolumePath + "/conf.json", "--workload"}
+	if workload.Kind == "deployment" {
+		args = append(args, "--repeat")
+	}
+	image := fmt.Sprintf("%s/%s:%s", s.HostnameFQDN(), s.ImageName, buildHash)
+	container := s.CreateLoadGeneratorContainer(s.ContainerName, image, s.ImagePullPolicy, s.VolumePath, s.VolumeName,
+		[]string{s.LoadGeneratorCommand}, args)
+
+	var workloadManifest interface{}
+	if workload.Kind == "deployment" {
+		workloadManifest = s.CreateLoadGeneratorDeployment(s.LoadGeneratorName, workload.Cluster, workload.Namespace, container, s.VolumeName, configMapName)
+	} else {
+		workloadManifest = s.CreateLoadGeneratorJob(s.LoadGeneratorName, workload.Cluster, workload.Namespace, container, s.VolumeName, configMapName)
+	}
 
-		cm_data := s.CreateConfigMap(processes, logging, protocol, config.Services[i].Endpoints)
+	return []interface{}{configmap, workloadManifest}
+}
+
+// Creates a Docker Compose file that runs the application on a single host
+// Every replica of a service on every cluster becomes a separate container
+func CreateComposeYaml(config model.FileConfig, buildHash string) {
+	path, _ := os.Getwd()
+	path = path + "/" + s.ComposeDirectory
+	os.MkdirAll(path+"/config", 0777)
+
+	image := fmt.Sprintf("%s/%s:%s", s.HostnameFQDN(), s.ImageName, buildHash)
+	compose := model.ComposeInstance{
+		Services: map[string]model.ComposeServiceInstance{},
+		Networks: map[string]model.ComposeNetworkInstance{
+			s.ComposeNetworkName: {Name: s.ComposeNetworkName},
+		},
+		Volumes: map[string]model.ComposeVolumeInstance{},
+	}
+	hostPort := s.ComposeHostPortDefault
+
+	for _, service := range config.Services {
+		storagePath := ""
+		if service.Storage != nil {
+			storagePath = s.StorageVolumePath
+		}
+
+		// Containers listen on the port that called services use by default, since there is no Kubernetes service in between
+		cm_data := s.CreateConfigMap(service.Processes, config.Settings.Logging, config.Settings.DeadlineReduction,
+			service.Resources.Limits.Cpu, storagePath, service.DisableOverrides, service.Protocol, service.Endpoints, service.Health)
+		cm_data.Port = s.DefaultExtPort
 
 		serv_json, err := json.Marshal(cm_data)
 		if err != nil {
 			panic(err)
 		}
+		err = os.WriteFile(fmt.Sprintf("%s/config/%s.json", path, service.Name), serv_json, 0644)
+		if err != nil {
+			panic(err)
+		}
 
-		for j := 0; j < len(config.Services[i].Clusters); j++ {
-			directory := config.Services[i].Clusters[j].Cluster
-			annotations := config.Services[i].Clusters[j].Annotations
-			replicas := config.Services[i].Clusters[j].Replicas
-
-			directory_path := fmt.Sprintf(path+"/%s", directory)
-			c_id := config.Services[i].Clusters[j].Cluster
-			nodeAffinity := config.Services[i].Clusters[j].Node
-			namespace := config.Services[i].Clusters[j].Namespace
-			manifestFilePath := fmt.Sprintf(directory_path+"/%s.yaml", serv)
-			manifests := make([]string, 0, 1)
-			appendManifest := func(manifest interface{}) error {
-				yamlDoc, err := yaml.Marshal(manifest)
-				if err != nil {
-					return err
-				}
-				manifests = append(manifests, string(yamlDoc))
-				return nil
-			}
-			configmap := s.CreateConfig("config-"+serv, "config-"+serv, c_id, namespace, string(serv_json))
-			appendManifest(configmap)
-
-			image := fmt.Sprintf("%s/%s:%s", s.HostnameFQDN(), s.ImageName, buildHash)
-			deployment := s.CreateDeployment(serv, serv, c_id, replicas, serv, c_id, namespace,
-				s.DefaultPort, s.ContainerName, image, s.ImagePullPolicy, s.VolumePath, s.VolumeName, "config-"+serv, readinessProbe,
-				resources.Requests.Cpu, resources.Requests.Memory, resources.Limits.Cpu, resources.Limits.Memory,
-				nodeAffinity, protocol, annotations)
-			appendManifest(deployment)
-
-			ports := []model.ServicePortInstance{
-				{
-					Name:       protocol,
-					Port:       s.DefaultExtPort,
-					TargetPort: s.DefaultPort,
-				},
+		// The service can be called by its name and by its name qualified with the namespace
+		aliases := []string{service.Name}
+		replicas := 0
+		for _, cluster := range service.Clusters {
+			aliases = append(aliases, service.Name+"."+cluster.Namespace)
+			replicas += cluster.Replicas
+			// There is no autoscaler, so autoscaled services run with their minimum number of replicas
+			if cluster.Autoscaling != nil {
+				replicas += cluster.Autoscaling.MinReplicas
 			}
+		}
+		aliases = Unique(aliases)
+		if replicas < 1 {
+			replicas = 1
+		}
 
-			service := s.CreateService(serv, serv, protocol, s.Uri, c_id, namespace, ports)
-			appendManifest(service)
+		for i := 1; i <= replicas; i++ {
+			name := service.Name
+			if replicas > 1 {
+				name = fmt.Sprintf("%s-replica-%d", service.Name, i)
+			}
 
-			yamlDocString := strings.Join(manifests, "---\n")
-			err := os.WriteFile(manifestFilePath, []byte(yamlDocString), 0644)
-			if err != nil {
-				fmt.Print(err)
-				return
+			composeService := s.CreateComposeService(service.Name, image, "./config/"+service.Name+".json", s.VolumePath,
+				s.ComposeNetworkName, aliases, hostPort, s.DefaultExtPort,
+				service.Resources.Requests.Cpu, service.Resources.Requests.Memory, service.Resources.Limits.Cpu, service.Resources.Limits.Memory)
+			if service.Storage != nil {
+				compose.Volumes[name+"-data"] = model.ComposeVolumeInstance{}
+				composeService.Volumes = append(composeService.Volumes, name+"-data:"+s.StorageVolumePath)
 			}
 
+			compose.Services[name] = composeService
+			hostPort++
 		}
 	}
+
+	if config.Workload != nil {
+		createLoadGeneratorCompose(config, &compose, path, image)
+	}
+
+	yamlDoc, err := yaml.Marshal(compose)
+	if err != nil {
+		panic(err)
+	}
+	err = os.WriteFile(path+"/docker-compose.yaml", yamlDoc, 0644)
+	if err != nil {
+		fmt.Print(err)
+	}
+}
+
+// Adds the load generator described by the workload section to the Docker Compose file
+func createLoadGeneratorCompose(config model.FileConfig, compose *model.ComposeInstance, path string, image string) {
+	config_json, err := json.Marshal(config)
+	if err != nil {
+		panic(err)
+	}
+	err = os.WriteFile(fmt.Sprintf("%s/config/%s.json", path, s.LoadGeneratorName), config_json, 0644)
+	if err != nil {
+		panic(err)
+	}
+
+	loadGenerator := model.ComposeServiceInstance{
+		Image:      image,
+		PullPolicy: "never",
+		Entrypoint: []string{s.LoadGeneratorCommand},
+		Command:    []string{"loadgen", s.VolumePath + "/conf.json", "--workload"},
+		Restart:    "no",
+		Volumes:    []string{fmt.Sprintf("./config/%s.json:%s/conf.json:ro", s.LoadGeneratorName, s.VolumePath)},
+		Networks:   map[string]model.ComposeServiceNetworkInstance{s.ComposeNetworkName: {}},
+	}
+	if config.Workload.Kind == "deployment" {
+		loadGenerator.Command = append(loadGenerator.Command, "--repeat")
+	}
+	for name := range compose.Services {
+		loadGenerator.DependsOn = append(loadGenerator.DependsOn, name)
+	}
+	sort.Strings(loadGenerator.DependsOn)
+
+	compose.Services[s.LoadGeneratorName] = loadGenerator
 }
 
 func CreateJsonInput(userConfig model.UserConfig, development bool) string {
@@ -312,6 +548,17 @@ func CreateJsonInput(userConfig model.UserConfig, development bool) string {
 		inputConfig.Services = append(inputConfig.Services, service)
 	}
 
+	// Services call services on other clusters, which they can only reach through multi-cluster discovery
+	usedClusters := map[string]bool{}
+	for _, service := range inputConfig.Services {
+		for _, cluster := range service.Clusters {
+			usedClusters[cluster.Cluster] = true
+		}
+	}
+	if len(usedClusters) > 1 {
+		inputConfig.Settings.Discovery = &model.Discovery{Method: s.DiscoveryMcs}
+	}
+
 	input_json, err := json.MarshalIndent(inputConfig, "", " ")
 	if err != nil {
 		panic(err)
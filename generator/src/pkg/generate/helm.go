@@ -0,0 +1,220 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	s "application-generator/src/pkg/service"
+	model "application-model"
+
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Returns a struct as the map it is encoded to in JSON, so that it can be embedded in Helm values
+func jsonMap(value interface{}) map[string]interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+
+	result := map[string]interface{}{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// Returns a manifest as the map it is encoded to in YAML, so that it can be embedded in Helm values
+func yamlMap(value interface{}) map[string]interface{} {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+
+	result := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func writeFile(filename string, data string) {
+	if err := os.WriteFile(filename, []byte(data), 0644); err != nil {
+		panic(err)
+	}
+}
+
+func writeYaml(filename string, value interface{}) {
+	yamlDoc, err := yaml.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	writeFile(filename, string(yamlDoc))
+}
+
+// Creates a Helm chart that deploys the application instead of the Kubernetes manifests
+// values.yaml contains the values shared by all clusters and values-<cluster>.yaml enables the services of a cluster
+func CreateHelmChart(config model.FileConfig, clusters []string, buildHash string) error {
+	path, _ := os.Getwd()
+	templatePath := path + "/template/helm/templates"
+	path = path + "/" + s.HelmDirectory
+	os.MkdirAll(path+"/templates", 0777)
+
+	// The templates do not depend on the input file and are copied as they are
+	templates, err := os.ReadDir(templatePath)
+	if err != nil {
+		panic(err)
+	}
+	for _, template := range templates {
+		data, err := os.ReadFile(filepath.Join(templatePath, template.Name()))
+		if err != nil {
+			panic(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "templates", template.Name()), data, 0644); err != nil {
+			panic(err)
+		}
+	}
+
+	chart := model.HelmChartInstance{
+		APIVersion:  "v2",
+		Name:        s.HelmChartName,
+		Description: "Microservice application emulated by HydraGen",
+		Type:        "application",
+		Version:     s.HelmChartVersion,
+		AppVersion:  buildHash,
+	}
+	writeYaml(path+"/Chart.yaml", chart)
+
+	values := model.HelmValuesInstance{
+		Logging:  config.Settings.Logging,
+		Services: map[string]model.HelmServiceValuesInstance{},
+	}
+	values.Image.Repository = fmt.Sprintf("%s/%s", s.HostnameFQDN(), s.ImageName)
+	values.Image.Tag = buildHash
+	values.Image.PullPolicy = s.ImagePullPolicy
+
+	clusterValues := map[string]*model.HelmClusterValuesInstance{}
+	for _, cluster := range Unique(clusters) {
+		clusterValues[cluster] = &model.HelmClusterValuesInstance{
+			Cluster:  cluster,
+			Services: map[string]model.HelmClusterServiceValuesInstance{},
+		}
+	}
+
+	for _, service := range config.Services {
+		storagePath := ""
+		if service.Storage != nil {
+			storagePath = s.StorageVolumePath
+		}
+		createConfig := func(endpoints []model.Endpoint) map[string]interface{} {
+			cm_data := s.CreateConfigMap(service.Processes, config.Settings.Logging, config.Settings.DeadlineReduction,
+				service.Resources.Limits.Cpu, storagePath, service.DisableOverrides, service.Protocol, endpoints, service.Health)
+			return jsonMap(cm_data)
+		}
+
+		serviceValues := model.HelmServiceValuesInstance{
+			Protocol:    service.Protocol,
+			Type:        serviceType(config, service),
+			Namespace:   s.ClusterNamespaceDefault,
+			Replicas:    1,
+			Strategy:    yamlMap(deploymentStrategy(service)),
+			Probes:      yamlMap(s.CreateProbes(s.DefaultPort, service.Protocol, *service.Probes)),
+			Annotations: map[string]string{},
+		}
+		serviceValues.Resources.Requests.Cpu = service.Resources.Requests.Cpu
+		serviceValues.Resources.Requests.Memory = service.Resources.Requests.Memory
+		serviceValues.Resources.Limits.Cpu = service.Resources.Limits.Cpu
+		serviceValues.Resources.Limits.Memory = service.Resources.Limits.Memory
+		if service.Storage != nil {
+			serviceValues.Storage = &model.HelmStorageValuesInstance{Size: service.Storage.Size, StorageClass: service.Storage.StorageClass}
+		}
+		if len(service.Versions) == 0 {
+			serviceValues.Config = createConfig(service.Endpoints)
+		} else {
+			serviceValues.Versions = map[string]model.HelmVersionValuesInstance{}
+			for _, version := range service.Versions {
+				serviceValues.Versions[version.Name] = model.HelmVersionValuesInstance{Config: createConfig(version.Endpoints)}
+			}
+		}
+		values.Services[service.Name] = serviceValues
+
+		for _, cluster := range service.Clusters {
+			// Kubernetes creates one replica if none is given
+			// Autoscaled services have 0 replicas, which leaves the replicas out so that upgrades don't reset the autoscaler
+			replicas := cluster.Replicas
+			if replicas < 1 && cluster.Autoscaling == nil {
+				replicas = 1
+			}
+
+			clusterServiceValues := model.HelmClusterServiceValuesInstance{
+				Enabled:   true,
+				Namespace: cluster.Namespace,
+				Replicas:  replicas,
+				Node:      cluster.Node,
+			}
+			if scheduling := yamlMap(s.CreateScheduling(service.Name, cluster, clusterNamespaces(config, cluster.Cluster))); len(scheduling) > 0 {
+				clusterServiceValues.Scheduling = scheduling
+			}
+			if len(cluster.Annotations) > 0 {
+				clusterServiceValues.Annotations = map[string]string{}
+				for _, annotation := range cluster.Annotations {
+					clusterServiceValues.Annotations[annotation.Name] = annotation.Value
+				}
+			}
+			for _, version := range service.Versions {
+				if version.Replicas > 0 {
+					if clusterServiceValues.Versions == nil {
+						clusterServiceValues.Versions = map[string]model.HelmClusterVersionValuesInstance{}
+					}
+					clusterServiceValues.Versions[version.Name] = model.HelmClusterVersionValuesInstance{Replicas: version.Replicas}
+				}
+			}
+			manifests, err := clusterManifests(config, service, cluster)
+			if err != nil {
+				return err
+			}
+			for _, manifest := range manifests {
+				clusterServiceValues.Manifests = append(clusterServiceValues.Manifests, yamlMap(manifest))
+			}
+			clusterValues[cluster.Cluster].Services[service.Name] = clusterServiceValues
+		}
+	}
+
+	if config.Workload != nil {
+		values.Workload = &model.HelmWorkloadValuesInstance{
+			Kind:      config.Workload.Kind,
+			Namespace: config.Workload.Namespace,
+			Config:    jsonMap(config),
+		}
+		clusterValues[config.Workload.Cluster].Workload = &model.HelmClusterWorkloadValuesInstance{Enabled: true}
+	}
+
+	writeYaml(path+"/values.yaml", values)
+	for cluster, values := range clusterValues {
+		writeYaml(fmt.Sprintf("%s/values-%s.yaml", path, cluster), values)
+
+		// Namespaces are applied before installing the release, since Helm can't adopt existing namespaces
+		if namespaces := namespaceManifests(config, cluster); len(namespaces) > 0 {
+			writeFile(fmt.Sprintf("%s/namespaces-%s.yaml", path, cluster), joinManifests(namespaces))
+		}
+	}
+	return nil
+}
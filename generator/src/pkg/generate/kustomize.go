@@ -0,0 +1,153 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	s "application-generator/src/pkg/service"
+	model "application-model"
+
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+func createKustomization(resources []string) model.KustomizationInstance {
+	return model.KustomizationInstance{
+		APIVersion: s.KustomizeAPIVersion,
+		Kind:       "Kustomization",
+		Resources:  resources,
+	}
+}
+
+// Returns a patch of a Deployment or StatefulSet of a service
+func createKustomizePatch(service model.Service, name string, operations []model.KustomizePatchOperationInstance) model.KustomizePatchInstance {
+	patch, err := yaml.Marshal(operations)
+	if err != nil {
+		panic(err)
+	}
+
+	var kustomizePatch model.KustomizePatchInstance
+	kustomizePatch.Target.Kind = "Deployment"
+	if service.Storage != nil {
+		kustomizePatch.Target.Kind = "StatefulSet"
+	}
+	kustomizePatch.Target.Name = name
+	kustomizePatch.Patch = string(patch)
+
+	return kustomizePatch
+}
+
+// Returns the overlay of a service on a cluster, which sets everything that differs between clusters
+func createKustomizeOverlay(config model.FileConfig, service model.Service, cluster model.Cluster) model.KustomizationInstance {
+	overlay := createKustomization([]string{fmt.Sprintf("../../../base/%s", service.Name)})
+	overlay.Namespace = cluster.Namespace
+	overlay.Labels = []model.KustomizeLabelInstance{
+		{Pairs: map[string]string{s.ClusterLabel: cluster.Cluster}, IncludeSelectors: true},
+	}
+	for _, version := range serviceVersions(service) {
+		if replicas := versionReplicas(version, cluster); replicas > 0 {
+			overlay.Replicas = append(overlay.Replicas, model.KustomizeReplicaInstance{Name: deploymentName(service, version), Count: replicas})
+		}
+	}
+
+	operations := []model.KustomizePatchOperationInstance{}
+	if cluster.Node != "" {
+		operations = append(operations, model.KustomizePatchOperationInstance{Op: "add", Path: "/spec/template/spec/nodeName", Value: cluster.Node})
+	}
+	// Every scheduling constraint is a field of the pod spec
+	scheduling := yamlMap(s.CreateScheduling(service.Name, cluster, clusterNamespaces(config, cluster.Cluster)))
+	fields := make([]string, 0, len(scheduling))
+	for field := range scheduling {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		operations = append(operations, model.KustomizePatchOperationInstance{Op: "add", Path: "/spec/template/spec/" + field, Value: scheduling[field]})
+	}
+	if len(cluster.Annotations) > 0 {
+		annotations := map[string]string{}
+		for _, annotation := range cluster.Annotations {
+			annotations[annotation.Name] = annotation.Value
+		}
+		operations = append(operations, model.KustomizePatchOperationInstance{Op: "add", Path: "/spec/template/metadata/annotations", Value: annotations})
+	}
+	if len(operations) > 0 {
+		for _, version := range serviceVersions(service) {
+			overlay.Patches = append(overlay.Patches, createKustomizePatch(service, deploymentName(service, version), operations))
+		}
+	}
+
+	return overlay
+}
+
+// Creates a Kustomize layout that deploys the application instead of the Kubernetes manifests
+// base/<service> contains the manifests shared by all clusters and overlays/<cluster> deploys the services of a cluster
+func CreateKustomize(config model.FileConfig, clusters []string, buildHash string) error {
+	path, _ := os.Getwd()
+	path = path + "/" + s.KustomizeDirectory
+
+	for _, service := range config.Services {
+		directory := fmt.Sprintf("%s/base/%s", path, service.Name)
+		os.MkdirAll(directory, 0777)
+
+		manifests := serviceManifests(config, service, model.Cluster{}, buildHash)
+		writeFile(fmt.Sprintf("%s/%s.yaml", directory, service.Name), joinManifests(manifests))
+		writeYaml(directory+"/kustomization.yaml", createKustomization([]string{service.Name + ".yaml"}))
+	}
+
+	for _, c_id := range Unique(clusters) {
+		directory := fmt.Sprintf("%s/overlays/%s", path, c_id)
+		os.MkdirAll(directory, 0777)
+
+		resources := []string{}
+		for _, service := range config.Services {
+			for _, cluster := range service.Clusters {
+				if cluster.Cluster != c_id {
+					continue
+				}
+
+				os.MkdirAll(directory+"/"+service.Name, 0777)
+				overlay := createKustomizeOverlay(config, service, cluster)
+				manifests, err := clusterManifests(config, service, cluster)
+				if err != nil {
+					return err
+				}
+				if len(manifests) > 0 {
+					writeFile(fmt.Sprintf("%s/%s/%s-%s.yaml", directory, service.Name, service.Name, c_id), joinManifests(manifests))
+					overlay.Resources = append(overlay.Resources, fmt.Sprintf("%s-%s.yaml", service.Name, c_id))
+				}
+				writeYaml(fmt.Sprintf("%s/%s/kustomization.yaml", directory, service.Name), overlay)
+				resources = append(resources, service.Name)
+			}
+		}
+
+		if config.Workload != nil && config.Workload.Cluster == c_id {
+			writeFile(fmt.Sprintf("%s/%s.yaml", directory, s.LoadGeneratorName), joinManifests(loadGeneratorManifests(config, buildHash)))
+			resources = append(resources, s.LoadGeneratorName+".yaml")
+		}
+
+		writeYaml(directory+"/kustomization.yaml", createKustomization(resources))
+
+		// Namespaces are applied separately, so they are not part of the overlay
+		if namespaces := namespaceManifests(config, c_id); len(namespaces) > 0 {
+			writeFile(directory+"/"+s.NamespacesFileName, joinManifests(namespaces))
+		}
+	}
+	return nil
+}
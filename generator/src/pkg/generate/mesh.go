@@ -0,0 +1,108 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	s "application-generator/src/pkg/service"
+	model "application-model"
+
+	"fmt"
+	"sort"
+)
+
+// Returns the mesh policy of a service, which is the default policy of the mesh section with the settings for the service applied
+// Settings for a service replace the default ones, including all fields of a connection pool, outlier detection or retries
+func meshPolicy(mesh *model.Mesh, service string) model.MeshPolicy {
+	policy := mesh.MeshPolicy
+
+	for _, meshService := range mesh.Services {
+		if meshService.Service != service {
+			continue
+		}
+
+		if meshService.Mtls != "" {
+			policy.Mtls = meshService.Mtls
+		}
+		if meshService.LoadBalancer != "" {
+			policy.LoadBalancer = meshService.LoadBalancer
+		}
+		if meshService.ConnectionPool != nil {
+			policy.ConnectionPool = meshService.ConnectionPool
+		}
+		if meshService.OutlierDetection != nil {
+			policy.OutlierDetection = meshService.OutlierDetection
+		}
+		if meshService.Timeout != 0 {
+			policy.Timeout = meshService.Timeout
+		}
+		if meshService.Retries != nil {
+			policy.Retries = meshService.Retries
+		}
+	}
+
+	return policy
+}
+
+// Returns the Istio resources of a service on a cluster
+func meshManifests(config model.FileConfig, service model.Service, cluster model.Cluster) []interface{} {
+	if config.Mesh == nil {
+		return nil
+	}
+
+	policy := meshPolicy(config.Mesh, service.Name)
+	host := fmt.Sprintf("%s.%s.svc.cluster.local", service.Name, cluster.Namespace)
+	versions := []string{}
+	for _, version := range service.Versions {
+		versions = append(versions, version.Name)
+	}
+
+	return []interface{}{
+		s.CreateVirtualService(service.Name, cluster.Cluster, cluster.Namespace, host, s.DefaultExtPort, policy.Timeout, policy.Retries,
+			service.Versions),
+		s.CreateDestinationRule(service.Name, cluster.Cluster, cluster.Namespace, host, policy.LoadBalancer,
+			policy.ConnectionPool, policy.OutlierDetection, versions),
+		s.CreatePeerAuthentication(service.Name, service.Name, cluster.Cluster, cluster.Namespace, policy.Mtls),
+	}
+}
+
+// Returns the namespaces used on a cluster with sidecar injection enabled, or no namespaces if injection is not needed
+// The namespaces are not labeled with the cluster so that pruning the manifests of a cluster never deletes them
+func namespaceManifests(config model.FileConfig, c_id string) []interface{} {
+	if config.Mesh == nil || config.Mesh.DisableInjection {
+		return nil
+	}
+
+	namespaces := []string{}
+	for _, service := range config.Services {
+		for _, cluster := range service.Clusters {
+			if cluster.Cluster == c_id {
+				namespaces = append(namespaces, cluster.Namespace)
+			}
+		}
+	}
+	if config.Workload != nil && config.Workload.Cluster == c_id {
+		namespaces = append(namespaces, config.Workload.Namespace)
+	}
+	namespaces = Unique(namespaces)
+	sort.Strings(namespaces)
+
+	manifests := []interface{}{}
+	for _, namespace := range namespaces {
+		manifests = append(manifests, s.CreateNamespace(namespace, map[string]string{s.MeshInjectionLabel: "enabled"}))
+	}
+	return manifests
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	s "application-generator/src/pkg/service"
+	model "application-model"
+)
+
+// Returns the NetworkPolicy of a service on a cluster, which only allows calls from its callers on the cluster and the load generator
+// Calls from other clusters or to entry points can't be told apart by their source, so such services accept calls from anywhere
+func networkPolicyManifests(config model.FileConfig, service model.Service, cluster model.Cluster) []interface{} {
+	if !config.Settings.NetworkPolicies {
+		return nil
+	}
+
+	peers := []model.NetworkPolicyPeerInstance{}
+	added := map[string]bool{}
+	addPeer := func(name, namespace string) {
+		peerNamespace := ""
+		if namespace != cluster.Namespace {
+			peerNamespace = namespace
+		}
+		if !added[name+"/"+peerNamespace] {
+			peers = append(peers, s.CreateNetworkPolicyPeer(name, peerNamespace))
+			added[name+"/"+peerNamespace] = true
+		}
+	}
+
+	// Entry points are called from outside the cluster
+	anySource := len(entryPoints(service)) > 0
+	for _, caller := range config.Services {
+		calls := false
+		for _, called := range calledServices(caller) {
+			calls = calls || called == service.Name
+		}
+		if !calls {
+			continue
+		}
+
+		for _, callerCluster := range caller.Clusters {
+			if callerCluster.Cluster == cluster.Cluster {
+				addPeer(caller.Name, callerCluster.Namespace)
+			} else if _, ok := clusterNamespaces(config, callerCluster.Cluster)[service.Name]; !ok {
+				anySource = true
+			}
+		}
+	}
+
+	if workload := config.Workload; workload != nil && workload.Cluster == cluster.Cluster {
+		for _, endpoint := range workload.Endpoints {
+			if endpoint.Service == service.Name {
+				addPeer(s.LoadGeneratorName, workload.Namespace)
+			}
+		}
+	}
+
+	return []interface{}{
+		s.CreateNetworkPolicy(service.Name, service.Name, cluster.Cluster, cluster.Namespace, s.DefaultPort, peers, anySource),
+	}
+}
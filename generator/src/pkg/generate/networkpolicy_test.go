@@ -0,0 +1,121 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	s "application-generator/src/pkg/service"
+	model "application-model"
+
+	"reflect"
+	"testing"
+)
+
+func TestNetworkPolicyManifests(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(config *model.FileConfig)
+		service   int
+		peers     []string
+		anySource bool
+	}{
+		{"caller", nil, 1, []string{"frontend"}, false},
+		{"no callers", nil, 0, []string{}, false},
+		{"caller in other namespace", func(config *model.FileConfig) {
+			config.Services[0].Clusters[0].Namespace = "frontend"
+		}, 1, []string{"frontend in frontend"}, false},
+		{"caller in several namespaces", func(config *model.FileConfig) {
+			config.Services[0].Clusters = append(config.Services[0].Clusters, model.Cluster{Cluster: "cluster1", Namespace: "frontend"})
+		}, 1, []string{"frontend", "frontend in frontend"}, false},
+		{"caller on other cluster", func(config *model.FileConfig) {
+			config.Services[0].Clusters[0].Cluster = "cluster2"
+			config.Settings.Discovery = &model.Discovery{Method: s.DiscoveryMcs}
+		}, 1, nil, true},
+		{"caller on other cluster with the called service", func(config *model.FileConfig) {
+			config.Services[0].Clusters = append(config.Services[0].Clusters, model.Cluster{Cluster: "cluster2", Namespace: "default"})
+			config.Services[1].Clusters = append(config.Services[1].Clusters, model.Cluster{Cluster: "cluster2", Namespace: "default"})
+		}, 1, []string{"frontend"}, false},
+		{"entry point", func(config *model.FileConfig) {
+			testEndpoint(config).EntryPoint = true
+		}, 1, nil, true},
+		{"load generator", func(config *model.FileConfig) {
+			config.Workload = testWorkload()
+		}, 0, []string{s.LoadGeneratorName}, false},
+		{"load generator in other namespace", func(config *model.FileConfig) {
+			config.Workload = testWorkload()
+			config.Workload.Namespace = "loadgen"
+		}, 0, []string{s.LoadGeneratorName + " in loadgen"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := loadTestDescription(t, func(config *model.FileConfig) {
+				config.Settings.NetworkPolicies = true
+				if test.modify != nil {
+					test.modify(config)
+				}
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			service := config.Services[test.service]
+			manifests := networkPolicyManifests(config, service, service.Clusters[0])
+			if len(manifests) != 1 {
+				t.Fatalf("expected one NetworkPolicy, got %d manifests", len(manifests))
+			}
+			policy := manifests[0].(model.NetworkPolicyInstance)
+			if policy.Metadata.Name != service.Name || policy.Metadata.Namespace != service.Clusters[0].Namespace {
+				t.Fatalf("expected NetworkPolicy '%s' in namespace '%s', got '%s' in '%s'",
+					service.Name, service.Clusters[0].Namespace, policy.Metadata.Name, policy.Metadata.Namespace)
+			}
+
+			ingress := policy.Spec.Ingress
+			if test.anySource {
+				if len(ingress) != 1 || ingress[0].From != nil {
+					t.Fatalf("expected requests from any source, got %+v", ingress)
+				}
+				return
+			}
+			peers := []string{}
+			for _, rule := range ingress {
+				for _, peer := range rule.From {
+					name := peer.PodSelector.MatchLabels["app"]
+					if peer.NamespaceSelector != nil {
+						name += " in " + peer.NamespaceSelector.MatchLabels[s.NamespaceNameLabel]
+					}
+					peers = append(peers, name)
+				}
+			}
+			if !reflect.DeepEqual(peers, test.peers) {
+				t.Fatalf("expected requests from %v, got %v", test.peers, peers)
+			}
+		})
+	}
+}
+
+func TestNetworkPolicyManifestsDisabled(t *testing.T) {
+	config, err := loadTestDescription(t, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, service := range config.Services {
+		if manifests := networkPolicyManifests(config, service, service.Clusters[0]); len(manifests) > 0 {
+			t.Fatalf("expected no NetworkPolicies without the network_policies setting, got %d", len(manifests))
+		}
+	}
+}
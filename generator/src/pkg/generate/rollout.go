@@ -0,0 +1,227 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	s "application-generator/src/pkg/service"
+	model "application-model"
+
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Returns the rolling update strategy of the deployments of a service, or nil if they use the default strategy
+func deploymentStrategy(service model.Service) *model.DeploymentStrategyInstance {
+	if service.Rollout == nil {
+		return nil
+	}
+	return s.CreateDeploymentStrategy(service.Rollout.MaxUnavailable, service.Rollout.MaxSurge)
+}
+
+// Returns the versions of a service, or a single unnamed version with the endpoints of the service if it has none
+func serviceVersions(service model.Service) []model.Version {
+	if len(service.Versions) == 0 {
+		return []model.Version{{Endpoints: service.Endpoints}}
+	}
+	return service.Versions
+}
+
+// Returns the name of the Deployment and config map of a version of a service
+func deploymentName(service model.Service, version model.Version) string {
+	if version.Name == "" {
+		return service.Name
+	}
+	return service.Name + "-" + version.Name
+}
+
+// Returns the number of replicas of a version of a service on a cluster
+// Unless it is given, every version runs as many replicas as the service on the cluster
+func versionReplicas(version model.Version, cluster model.Cluster) int {
+	if version.Replicas > 0 {
+		return version.Replicas
+	}
+	return cluster.Replicas
+}
+
+// Returns whether the requests to a service are split between its versions by weight
+func weighted(service model.Service) bool {
+	for _, version := range service.Versions {
+		if version.Weight > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the backends of a Gateway API route to a service
+// Requests to a service with weights are split between the Services of its versions, which are left out if they get no requests
+func versionBackendRefs(service model.Service) []model.GatewayBackendRefInstance {
+	if !weighted(service) {
+		return []model.GatewayBackendRefInstance{{Name: service.Name, Port: s.DefaultExtPort}}
+	}
+
+	backendRefs := []model.GatewayBackendRefInstance{}
+	for _, version := range service.Versions {
+		if version.Weight > 0 {
+			backendRefs = append(backendRefs, model.GatewayBackendRefInstance{Name: deploymentName(service, version), Port: s.DefaultExtPort, Weight: version.Weight})
+		}
+	}
+	return backendRefs
+}
+
+// Returns the Services of the versions of a service on a cluster, and the Gateway API route that splits requests between them
+// With a service mesh, requests are split by its VirtualService instead
+func versionManifests(config model.FileConfig, service model.Service, cluster model.Cluster) []interface{} {
+	if len(service.Versions) == 0 {
+		return nil
+	}
+
+	ports := []model.ServicePortInstance{
+		{
+			Name:       service.Protocol,
+			Port:       s.DefaultExtPort,
+			TargetPort: s.DefaultPort,
+		},
+	}
+
+	manifests := []interface{}{}
+	for _, version := range service.Versions {
+		manifests = append(manifests, s.CreateService(deploymentName(service, version), service.Name, version.Name, service.Protocol, s.Uri,
+			cluster.Cluster, cluster.Namespace, "", ports))
+	}
+
+	if !weighted(service) || !config.Settings.Gamma {
+		return manifests
+	}
+	if service.Protocol == "grpc" {
+		return append(manifests, s.CreateServiceGRPCRoute(service.Name+s.RolloutRouteSuffix, cluster.Cluster, cluster.Namespace, service.Name, s.DefaultExtPort,
+			versionBackendRefs(service)))
+	}
+	return append(manifests, s.CreateServiceHTTPRoute(service.Name+s.RolloutRouteSuffix, cluster.Cluster, cluster.Namespace, service.Name, s.DefaultExtPort,
+		versionBackendRefs(service)))
+}
+
+// Returns the PodDisruptionBudget of a service on a cluster, which covers the pods of all its versions
+func disruptionBudgetManifests(service model.Service, cluster model.Cluster) []interface{} {
+	if service.Rollout == nil || service.Rollout.DisruptionBudget == nil {
+		return nil
+	}
+
+	budget := service.Rollout.DisruptionBudget
+	return []interface{}{
+		s.CreatePodDisruptionBudget(service.Name, service.Name, cluster.Cluster, cluster.Namespace, budget.MinAvailable, budget.MaxUnavailable),
+	}
+}
+
+// Returns a copy of endpoints that shares no complexities with them
+func copyEndpoints(endpoints []model.Endpoint) []model.Endpoint {
+	// The endpoints are copied through JSON, since their complexities are pointers
+	data, err := json.Marshal(endpoints)
+	if err != nil {
+		panic(err)
+	}
+
+	copied := []model.Endpoint{}
+	if err := json.Unmarshal(data, &copied); err != nil {
+		panic(err)
+	}
+	return copied
+}
+
+// Multiplies the time that the stressors of an endpoint take by factor
+func scaleStressors(endpoint *model.Endpoint, factor float64) {
+	scale := func(payloadComplexity *model.PayloadComplexity, cpuComplexity *model.CpuComplexity,
+		lockComplexity *model.LockComplexity, storageComplexity *model.StorageComplexity) {
+
+		if payloadComplexity != nil {
+			payloadComplexity.CpuTimePerKb *= float32(factor)
+		}
+		if cpuComplexity != nil {
+			cpuComplexity.ExecutionTime *= float32(factor)
+			cpuComplexity.WorkUnits *= float32(factor)
+		}
+		if lockComplexity != nil {
+			lockComplexity.HoldTime *= float32(factor)
+		}
+		if storageComplexity != nil {
+			storageComplexity.Operations = int(math.Max(1, math.Round(float64(storageComplexity.Operations)*factor)))
+		}
+	}
+
+	scale(endpoint.PayloadComplexity, endpoint.CpuComplexity, endpoint.LockComplexity, endpoint.StorageComplexity)
+	for i := range endpoint.Steps {
+		step := &endpoint.Steps[i]
+		scale(step.PayloadComplexity, step.CpuComplexity, step.LockComplexity, step.StorageComplexity)
+	}
+}
+
+// Adds a second version named v2 to the given services, whose endpoints take factor times as long as the endpoints of their first version
+// Services without versions get a first version named v1 with their endpoints, and the endpoints of an existing v2 are replaced
+// With a weight, v2 gets this percentage of the requests and the first version the rest
+// The resulting config is validated again, since the second versions have to be valid in the input as well
+func CreateV2(config model.FileConfig, services []string, factor float64, weight int) (model.FileConfig, error) {
+	if factor <= 0 {
+		return config, fmt.Errorf("factor of the second versions must be positive, got %g", factor)
+	}
+	if weight < 0 || weight > 100 {
+		return config, fmt.Errorf("weight of the second versions must be a percentage, got %d", weight)
+	}
+
+	for _, name := range services {
+		var service *model.Service
+		for i := range config.Services {
+			if config.Services[i].Name == name {
+				service = &config.Services[i]
+			}
+		}
+		if service == nil {
+			return config, fmt.Errorf("can't create a second version of service '%s', which is not in the input", name)
+		}
+
+		if len(service.Versions) == 0 {
+			service.Versions = []model.Version{{Name: s.RolloutV1Name, Endpoints: copyEndpoints(service.Endpoints)}}
+		}
+		versions := []model.Version{}
+		v2 := model.Version{Name: s.RolloutV2Name}
+		for _, version := range service.Versions {
+			if version.Name == s.RolloutV2Name {
+				v2 = version
+			} else {
+				versions = append(versions, version)
+			}
+		}
+		if len(versions) == 0 {
+			return config, fmt.Errorf("service '%s' has no version besides %s", name, s.RolloutV2Name)
+		}
+
+		v2.Endpoints = copyEndpoints(versions[0].Endpoints)
+		for i := range v2.Endpoints {
+			scaleStressors(&v2.Endpoints[i], factor)
+		}
+		if weight > 0 {
+			for i := range versions {
+				versions[i].Weight = 0
+			}
+			versions[0].Weight = 100 - weight
+			v2.Weight = weight
+		}
+		service.Versions = append(versions, v2)
+	}
+
+	return config, ValidateFileConfig(&config)
+}
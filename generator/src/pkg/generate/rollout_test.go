@@ -0,0 +1,175 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	s "application-generator/src/pkg/service"
+	model "application-model"
+
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestVersionBackendRefs(t *testing.T) {
+	tests := []struct {
+		name        string
+		versions    []model.Version
+		backendRefs []model.GatewayBackendRefInstance
+	}{
+		{"no versions", nil, []model.GatewayBackendRefInstance{{Name: "backend", Port: s.DefaultExtPort}}},
+		{"no weights", []model.Version{{Name: "v1"}, {Name: "v2"}}, []model.GatewayBackendRefInstance{{Name: "backend", Port: s.DefaultExtPort}}},
+		{"weights", []model.Version{{Name: "v1", Weight: 90}, {Name: "v2", Weight: 10}}, []model.GatewayBackendRefInstance{
+			{Name: "backend-v1", Port: s.DefaultExtPort, Weight: 90},
+			{Name: "backend-v2", Port: s.DefaultExtPort, Weight: 10},
+		}},
+		{"version without requests", []model.Version{{Name: "v1", Weight: 100}, {Name: "v2"}}, []model.GatewayBackendRefInstance{
+			{Name: "backend-v1", Port: s.DefaultExtPort, Weight: 100},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backendRefs := versionBackendRefs(model.Service{Name: "backend", Versions: test.versions})
+			if !reflect.DeepEqual(backendRefs, test.backendRefs) {
+				t.Fatalf("expected backends %+v, got %+v", test.backendRefs, backendRefs)
+			}
+		})
+	}
+}
+
+func TestVersionManifests(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(config *model.FileConfig)
+		manifests []string
+	}{
+		{"no versions", nil, nil},
+		{"no weights", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "v1"}, {Name: "v2"}}
+		}, []string{"Service backend-v1", "Service backend-v2"}},
+		{"weights with mesh", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "v1", Weight: 90}, {Name: "v2", Weight: 10}}
+			config.Mesh = &model.Mesh{}
+		}, []string{"Service backend-v1", "Service backend-v2"}},
+		{"weights with gamma", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "v1", Weight: 90}, {Name: "v2", Weight: 10}}
+			config.Settings.Gamma = true
+		}, []string{"Service backend-v1", "Service backend-v2", "HTTPRoute backend" + s.RolloutRouteSuffix}},
+		{"gRPC weights with gamma", func(config *model.FileConfig) {
+			config.Services[1].Protocol = "grpc"
+			config.Services[1].Versions = []model.Version{{Name: "v1", Weight: 90}, {Name: "v2", Weight: 10}}
+			config.Settings.Gamma = true
+		}, []string{"Service backend-v1", "Service backend-v2", "GRPCRoute backend" + s.RolloutRouteSuffix}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := loadTestDescription(t, test.modify)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			service := config.Services[1]
+			manifests := versionManifests(config, service, service.Clusters[0])
+			if test.manifests == nil {
+				if len(manifests) > 0 {
+					t.Fatalf("expected no manifests, got %v", manifestNames(manifests))
+				}
+				return
+			}
+			if names := manifestNames(manifests); !reflect.DeepEqual(names, test.manifests) {
+				t.Fatalf("expected manifests %v, got %v", test.manifests, names)
+			}
+		})
+	}
+}
+
+func TestCreateV2(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(config *model.FileConfig)
+		services []string
+		factor   float64
+		weight   int
+		versions []string
+		err      string
+	}{
+		{"service without versions", nil, []string{"backend"}, 2, 10, []string{"v1:90", "v2:10"}, ""},
+		{"no weight", nil, []string{"backend"}, 2, 0, []string{"v1:0", "v2:0"}, ""},
+		{"all requests", nil, []string{"backend"}, 0.5, 100, []string{"v1:0", "v2:100"}, ""},
+		{"service with versions", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "stable", Weight: 90}, {Name: "canary", Weight: 10}}
+		}, []string{"backend"}, 2, 20, []string{"stable:80", "canary:0", "v2:20"}, ""},
+		{"service with v2", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "v2", Weight: 50}, {Name: "v1", Weight: 50}}
+		}, []string{"backend"}, 2, 30, []string{"v1:70", "v2:30"}, ""},
+		{"several services", nil, []string{"frontend", "backend"}, 2, 10, []string{"v1:90", "v2:10"}, ""},
+		{"factor of 0", nil, []string{"backend"}, 0, 10, nil, "factor of the second versions must be positive, got 0"},
+		{"weight above 100", nil, []string{"backend"}, 2, 110, nil, "weight of the second versions must be a percentage, got 110"},
+		{"unknown service", nil, []string{"database"}, 2, 10, nil, "can't create a second version of service 'database', which is not in the input"},
+		{"service with only v2", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "v2"}}
+		}, []string{"backend"}, 2, 10, nil, "service 'backend' has no version besides v2"},
+		{"weight without gamma", func(config *model.FileConfig) {
+			config.Settings.Gamma = false
+		}, []string{"backend"}, 2, 10, nil, "weights of the versions of service 'backend' need a service mesh or the gamma setting"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := loadTestDescription(t, func(config *model.FileConfig) {
+				config.Settings.Gamma = true
+				if test.modify != nil {
+					test.modify(config)
+				}
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			config, err = CreateV2(config, test.services, test.factor, test.weight)
+			if test.err != "" {
+				if err == nil || err.Error() != test.err {
+					t.Fatalf("expected error '%s', got: %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			service := config.Services[1]
+			versions := []string{}
+			for _, version := range service.Versions {
+				versions = append(versions, fmt.Sprintf("%s:%d", version.Name, version.Weight))
+			}
+			if !reflect.DeepEqual(versions, test.versions) {
+				t.Fatalf("expected versions %v, got %v", test.versions, versions)
+			}
+
+			// The CPU time of the endpoint of backend is scaled in v2 only
+			first, v2 := service.Versions[0], service.Versions[len(service.Versions)-1]
+			if executionTime := first.Endpoints[0].CpuComplexity.ExecutionTime; executionTime != 0.001 {
+				t.Fatalf("expected CPU execution time 0.001 in version '%s', got %g", first.Name, executionTime)
+			}
+			if executionTime := v2.Endpoints[0].CpuComplexity.ExecutionTime; executionTime != 0.001*float32(test.factor) {
+				t.Fatalf("expected CPU execution time %g in v2, got %g", 0.001*float32(test.factor), executionTime)
+			}
+		})
+	}
+}
@@ -22,6 +22,9 @@ import (
 
 	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -36,6 +39,20 @@ func Occurrences(strSlice []string) map[string]int {
 	return occurrences
 }
 
+// Returns the network complexity of an endpoint and all of its steps
+func networkComplexities(endpoint *model.Endpoint) []*model.NetworkComplexity {
+	complexities := []*model.NetworkComplexity{}
+	if endpoint.NetworkComplexity != nil {
+		complexities = append(complexities, endpoint.NetworkComplexity)
+	}
+	for _, step := range endpoint.Steps {
+		if step.NetworkComplexity != nil {
+			complexities = append(complexities, step.NetworkComplexity)
+		}
+	}
+	return complexities
+}
+
 // Validates service and endpoint names in JSON config
 func ValidateNames(config *model.FileConfig) error {
 	serviceNames := []string{}
@@ -49,6 +66,10 @@ func ValidateNames(config *model.FileConfig) error {
 		if len(errs) > 0 {
 			return fmt.Errorf("service '%s' has invalid name: %s", service.Name, errs[0])
 		}
+		// The load generator's manifests are named after it, so a service with its name would overwrite them
+		if service.Name == s.LoadGeneratorName {
+			return fmt.Errorf("service name '%s' is reserved for the load generator", service.Name)
+		}
 
 		serviceNames = append(serviceNames, service.Name)
 	}
@@ -69,8 +90,8 @@ func ValidateNames(config *model.FileConfig) error {
 				return fmt.Errorf("endpoint '%s' has invalid name: %s", endpoint.Name, errs[0])
 			}
 
-			if endpoint.NetworkComplexity != nil {
-				for _, calledService := range endpoint.NetworkComplexity.CalledServices {
+			for _, networkComplexity := range networkComplexities(&endpoint) {
+				for _, calledService := range networkComplexity.CalledServices {
 					errs = validation.IsDNS1035Label(calledService.Service)
 					if len(errs) > 0 {
 						return fmt.Errorf("call from endpoint '%s' to invalid service '%s': %s", endpoint.Name, calledService.Service, errs[0])
@@ -96,9 +117,51 @@ func ValidateNames(config *model.FileConfig) error {
 	return nil
 }
 
+// Compares two valid quantities, such as a request and a limit
+func compareQuantities(a, b string) int {
+	quantity := resource.MustParse(a)
+	return quantity.Cmp(resource.MustParse(b))
+}
+
+// Returns the resource profiles services can use, the profiles in the settings replace the built-in profiles with the same name
+func resourceProfiles(config *model.FileConfig) map[string]model.Resources {
+	profiles := s.ResourceProfiles()
+	for name, profile := range config.Settings.Profiles {
+		profiles[name] = profile
+	}
+	return profiles
+}
+
 // Validates resource limits in input JSON
 func ValidateResources(config *model.FileConfig) error {
+	validQosClasses := map[string]bool{"": true, "Guaranteed": true, "Burstable": true, "BestEffort": true}
+
+	for name, profile := range config.Settings.Profiles {
+		limits := []string{profile.Limits.Cpu, profile.Limits.Memory, profile.Requests.Cpu, profile.Requests.Memory}
+		for _, limit := range limits {
+			if _, err := resource.ParseQuantity(limit); limit != "" && err != nil {
+				return fmt.Errorf("resource profile '%s' has invalid resource allocation '%s': %s", name, limit, err)
+			}
+		}
+	}
+
+	profiles := resourceProfiles(config)
 	for _, service := range config.Services {
+		if _, ok := profiles[service.Profile]; service.Profile != "" && !ok {
+			return fmt.Errorf("service '%s' uses unknown resource profile '%s'", service.Name, service.Profile)
+		}
+		if !validQosClasses[service.QosClass] {
+			return fmt.Errorf("service '%s' has invalid QoS class '%s' (Guaranteed, Burstable, BestEffort)", service.Name, service.QosClass)
+		}
+
+		// Pods are only BestEffort if none of their containers have requests or limits
+		if service.QosClass == "BestEffort" {
+			if service.Resources != (model.Resources{}) {
+				return fmt.Errorf("service '%s' can't have resource allocations in QoS class BestEffort", service.Name)
+			}
+			continue
+		}
+
 		limits := []string{
 			service.Resources.Limits.Cpu,
 			service.Resources.Limits.Memory,
@@ -115,6 +178,30 @@ func ValidateResources(config *model.FileConfig) error {
 				return fmt.Errorf("resource allocation '%s' too low", limit)
 			}
 		}
+
+		allocations := []struct {
+			resource       string
+			request, limit string
+		}{
+			{"CPU", service.Resources.Requests.Cpu, service.Resources.Limits.Cpu},
+			{"memory", service.Resources.Requests.Memory, service.Resources.Limits.Memory},
+		}
+		equal := true
+		for _, allocation := range allocations {
+			comparison := compareQuantities(allocation.request, allocation.limit)
+			if comparison > 0 {
+				return fmt.Errorf("service '%s' has %s request '%s' greater than its limit '%s'",
+					service.Name, allocation.resource, allocation.request, allocation.limit)
+			}
+			equal = equal && comparison == 0
+		}
+
+		if service.QosClass == "Guaranteed" && !equal {
+			return fmt.Errorf("service '%s' needs requests equal to its limits for QoS class Guaranteed", service.Name)
+		}
+		if service.QosClass == "Burstable" && equal {
+			return fmt.Errorf("service '%s' needs a request lower than its limit for QoS class Burstable", service.Name)
+		}
 	}
 
 	return nil
@@ -129,8 +216,8 @@ func ValidateProtocols(service *model.Service) error {
 	}
 
 	for _, endpoint := range service.Endpoints {
-		if endpoint.NetworkComplexity != nil {
-			for _, calledService := range endpoint.NetworkComplexity.CalledServices {
+		for _, networkComplexity := range networkComplexities(&endpoint) {
+			for _, calledService := range networkComplexity.CalledServices {
 				if !validProtocols[calledService.Protocol] {
 					return fmt.Errorf("call to endpoint '%s' from endpoint '%s' has invalid protocol '%s'",
 						calledService.Endpoint, endpoint.Name, calledService.Protocol)
@@ -142,117 +229,1387 @@ func ValidateProtocols(service *model.Service) error {
 	return nil
 }
 
-// Validate that input JSON contains required parameters
-func ValidateRequiredParameters(config *model.FileConfig) error {
-	if len(config.Services) == 0 {
-		return errors.New("at least one service is required")
+// Validate that hedging policies have a delay and sensible limits
+func ValidateHedging(service *model.Service) error {
+	for _, endpoint := range service.Endpoints {
+		for _, networkComplexity := range networkComplexities(&endpoint) {
+			for _, calledService := range networkComplexity.CalledServices {
+				policy := calledService.Hedging
+				if policy == nil {
+					continue
+				}
+
+				if policy.Delay < 0 {
+					return fmt.Errorf("call to endpoint '%s' from endpoint '%s' has negative hedging delay",
+						calledService.Endpoint, endpoint.Name)
+				}
+				if policy.Percentile < 0 || policy.Percentile >= 100 {
+					return fmt.Errorf("call to endpoint '%s' from endpoint '%s' has invalid hedging percentile %g (0-100)",
+						calledService.Endpoint, endpoint.Name, policy.Percentile)
+				}
+				if policy.Delay == 0 && policy.Percentile == 0 {
+					return fmt.Errorf("call to endpoint '%s' from endpoint '%s' needs a hedging delay or percentile",
+						calledService.Endpoint, endpoint.Name)
+				}
+				if policy.MaxHedges < 1 {
+					return fmt.Errorf("call to endpoint '%s' from endpoint '%s' has invalid number of hedges",
+						calledService.Endpoint, endpoint.Name)
+				}
+			}
+		}
 	}
 
-	for _, service := range config.Services {
-		if len(service.Clusters) == 0 {
-			return fmt.Errorf("service '%s' needs to be deployed on at least one cluster", service.Name)
+	return nil
+}
+
+// Validate that CPU stressors have the parameters required by their mode
+func ValidateCpuModes(service *model.Service) error {
+	validKernels := map[string]bool{
+		"busy": true, "sha256": true, "json": true, "protobuf": true, "compression": true, "matrix": true, "pointer_chase": true,
+	}
+
+	validate := func(endpoint *model.Endpoint, cpuComplexity *model.CpuComplexity, inStep bool) error {
+		if cpuComplexity.Mode != "time" && cpuComplexity.Kernel != "" {
+			return fmt.Errorf("endpoint '%s' in service '%s' can only use CPU kernels in time mode", endpoint.Name, service.Name)
 		}
-		if service.Processes < 0 {
-			return fmt.Errorf("service '%s' has invalid number of processes (0 = auto, >0 = manual)", service.Name)
+
+		switch cpuComplexity.Mode {
+		case "time":
+			if cpuComplexity.ExecutionTime < 0 {
+				return fmt.Errorf("endpoint '%s' in service '%s' has negative CPU execution time", endpoint.Name, service.Name)
+			}
+			if !validKernels[cpuComplexity.Kernel] {
+				return fmt.Errorf("endpoint '%s' in service '%s' has invalid CPU kernel '%s'", endpoint.Name, service.Name, cpuComplexity.Kernel)
+			}
+			if cpuComplexity.WorkingSetSize < 0 {
+				return fmt.Errorf("endpoint '%s' in service '%s' has negative CPU working set size", endpoint.Name, service.Name)
+			}
+		case "work":
+			if cpuComplexity.WorkUnits <= 0 {
+				return fmt.Errorf("endpoint '%s' in service '%s' needs a positive number of CPU work units", endpoint.Name, service.Name)
+			}
+		case "utilization":
+			if inStep {
+				return fmt.Errorf("endpoint '%s' in service '%s' can't use CPU utilization in steps", endpoint.Name, service.Name)
+			}
+			if cpuComplexity.Utilization <= 0 || cpuComplexity.Utilization > 1 {
+				return fmt.Errorf("endpoint '%s' in service '%s' has invalid CPU utilization %g (0-1)", endpoint.Name, service.Name, cpuComplexity.Utilization)
+			}
+		default:
+			return fmt.Errorf("endpoint '%s' in service '%s' has invalid CPU mode '%s'", endpoint.Name, service.Name, cpuComplexity.Mode)
 		}
 
-		if len(service.Endpoints) == 0 {
-			return fmt.Errorf("at least one endpoint is required in service '%s'", service.Name)
-		} else {
-			err := ValidateProtocols(&service)
-			if err != nil {
+		return nil
+	}
+
+	// The service runs a single background stressor, so all endpoints in utilization mode need the same utilization
+	var utilization float32
+	for _, endpoint := range service.Endpoints {
+		if endpoint.CpuComplexity != nil {
+			if err := validate(&endpoint, endpoint.CpuComplexity, false); err != nil {
 				return err
 			}
+			if endpoint.CpuComplexity.Mode == "utilization" {
+				if utilization != 0 && endpoint.CpuComplexity.Utilization != utilization {
+					return fmt.Errorf("endpoints in service '%s' have different CPU utilizations, but the service has a single background utilization", service.Name)
+				}
+				utilization = endpoint.CpuComplexity.Utilization
+			}
+		}
+		for _, step := range endpoint.Steps {
+			if step.CpuComplexity != nil {
+				if err := validate(&endpoint, step.CpuComplexity, true); err != nil {
+					return err
+				}
+			}
 		}
 	}
 
 	return nil
 }
 
-// Validates an input JSON config provided by the user
-func ValidateFileConfig(config *model.FileConfig) error {
-	if err := ValidateRequiredParameters(config); err != nil {
-		return err
+// Validate that lock stressors have a valid pool size and critical section
+func ValidateLocks(service *model.Service) error {
+	validHoldModes := map[string]bool{"cpu": true, "sleep": true}
+
+	for _, endpoint := range service.Endpoints {
+		lockComplexities := []*model.LockComplexity{endpoint.LockComplexity}
+		for _, step := range endpoint.Steps {
+			lockComplexities = append(lockComplexities, step.LockComplexity)
+		}
+
+		for _, lockComplexity := range lockComplexities {
+			if lockComplexity == nil {
+				continue
+			}
+
+			if lockComplexity.Locks < 1 {
+				return fmt.Errorf("endpoint '%s' in service '%s' needs at least one lock", endpoint.Name, service.Name)
+			}
+			if lockComplexity.HoldTime < 0 {
+				return fmt.Errorf("endpoint '%s' in service '%s' has negative lock hold time", endpoint.Name, service.Name)
+			}
+			if !validHoldModes[lockComplexity.HoldMode] {
+				return fmt.Errorf("endpoint '%s' in service '%s' has invalid lock hold mode '%s'", endpoint.Name, service.Name, lockComplexity.HoldMode)
+			}
+		}
 	}
-	if err := ValidateNames(config); err != nil {
-		return err
+
+	return nil
+}
+
+// Validate that payload processing and generated payloads use known content types and transforms
+func ValidatePayloads(service *model.Service) error {
+	validContentTypes := map[string]bool{"text": true, "json": true, "bytes": true}
+	validTransforms := map[string]bool{"none": true, "compress": true, "base64": true, "json": true}
+
+	for _, endpoint := range service.Endpoints {
+		payloadComplexities := []*model.PayloadComplexity{endpoint.PayloadComplexity}
+		for _, step := range endpoint.Steps {
+			payloadComplexities = append(payloadComplexities, step.PayloadComplexity)
+		}
+
+		for _, payloadComplexity := range payloadComplexities {
+			if payloadComplexity == nil {
+				continue
+			}
+
+			if !validContentTypes[payloadComplexity.ContentType] {
+				return fmt.Errorf("endpoint '%s' in service '%s' has invalid payload content type '%s'", endpoint.Name, service.Name, payloadComplexity.ContentType)
+			}
+			if !validTransforms[payloadComplexity.Transform] {
+				return fmt.Errorf("endpoint '%s' in service '%s' has invalid payload transform '%s'", endpoint.Name, service.Name, payloadComplexity.Transform)
+			}
+			if payloadComplexity.CpuTimePerKb < 0 {
+				return fmt.Errorf("endpoint '%s' in service '%s' has negative payload CPU time", endpoint.Name, service.Name)
+			}
+		}
+
+		for _, networkComplexity := range networkComplexities(&endpoint) {
+			if !validContentTypes[networkComplexity.ResponsePayloadContent] {
+				return fmt.Errorf("endpoint '%s' in service '%s' has invalid response payload content '%s'", endpoint.Name, service.Name, networkComplexity.ResponsePayloadContent)
+			}
+			for _, calledService := range networkComplexity.CalledServices {
+				if !validContentTypes[calledService.RequestPayloadContent] {
+					return fmt.Errorf("call to endpoint '%s' from endpoint '%s' has invalid request payload content '%s'",
+						calledService.Endpoint, endpoint.Name, calledService.RequestPayloadContent)
+				}
+			}
+		}
 	}
-	if err := ValidateResources(config); err != nil {
-		return err
+
+	return nil
+}
+
+// Validate that key-value store parameters are in range and persisted stores have a volume
+func ValidateStorage(service *model.Service) error {
+	validDistributions := map[string]bool{"uniform": true, "zipf": true}
+
+	if service.Storage != nil {
+		if _, err := resource.ParseQuantity(service.Storage.Size); err != nil {
+			return fmt.Errorf("service '%s' has invalid storage size '%s': %s", service.Name, service.Storage.Size, err)
+		}
+	}
+
+	for _, endpoint := range service.Endpoints {
+		storageComplexities := []*model.StorageComplexity{endpoint.StorageComplexity}
+		for _, step := range endpoint.Steps {
+			storageComplexities = append(storageComplexities, step.StorageComplexity)
+		}
+
+		for _, storageComplexity := range storageComplexities {
+			if storageComplexity == nil {
+				continue
+			}
+
+			if storageComplexity.Keys < 1 {
+				return fmt.Errorf("endpoint '%s' in service '%s' needs at least one storage key", endpoint.Name, service.Name)
+			}
+			if storageComplexity.ValueSize < 0 {
+				return fmt.Errorf("endpoint '%s' in service '%s' has negative storage value size", endpoint.Name, service.Name)
+			}
+			if storageComplexity.Operations < 1 {
+				return fmt.Errorf("endpoint '%s' in service '%s' needs at least one storage operation", endpoint.Name, service.Name)
+			}
+			if storageComplexity.ReadRatio < 0 || storageComplexity.ReadRatio > 1 {
+				return fmt.Errorf("endpoint '%s' in service '%s' has invalid storage read ratio %g (0-1)", endpoint.Name, service.Name, storageComplexity.ReadRatio)
+			}
+			if !validDistributions[storageComplexity.Distribution] {
+				return fmt.Errorf("endpoint '%s' in service '%s' has invalid storage key distribution '%s'", endpoint.Name, service.Name, storageComplexity.Distribution)
+			}
+			if storageComplexity.Distribution == "zipf" && storageComplexity.ZipfExponent <= 1 {
+				return fmt.Errorf("endpoint '%s' in service '%s' has invalid Zipf exponent %g (>1)", endpoint.Name, service.Name, storageComplexity.ZipfExponent)
+			}
+			if storageComplexity.Persist && service.Storage == nil {
+				return fmt.Errorf("endpoint '%s' in service '%s' persists storage, but the service has no storage volume", endpoint.Name, service.Name)
+			}
+		}
 	}
 
 	return nil
 }
 
-// Applies default values to input JSON
-func ApplyDefaults(config *model.FileConfig) {
-	if config.Settings.BaseImage == "" {
-		config.Settings.BaseImage = s.BaseImageDefault
+// Validate that endpoint steps are not mixed with endpoint stressors and are not empty
+func ValidateSteps(service *model.Service) error {
+	for _, endpoint := range service.Endpoints {
+		if len(endpoint.Steps) == 0 {
+			continue
+		}
+
+		if endpoint.PayloadComplexity != nil || endpoint.CpuComplexity != nil || endpoint.LockComplexity != nil ||
+			endpoint.StorageComplexity != nil || endpoint.NetworkComplexity != nil {
+			return fmt.Errorf("endpoint '%s' in service '%s' can't define stressors both in steps and outside of steps",
+				endpoint.Name, service.Name)
+		}
+
+		for i, step := range endpoint.Steps {
+			if step.PayloadComplexity == nil && step.CpuComplexity == nil && step.LockComplexity == nil &&
+				step.StorageComplexity == nil && step.NetworkComplexity == nil {
+				return fmt.Errorf("step %d of endpoint '%s' in service '%s' has no stressors",
+					i+1, endpoint.Name, service.Name)
+			}
+		}
 	}
 
-	for i := range config.Services {
-		service := &config.Services[i]
+	return nil
+}
+
+// Validate that autoscaled services have a valid range of replicas and something to scale on
+func ValidateAutoscaling(service *model.Service) error {
+	for _, cluster := range service.Clusters {
+		autoscaling := cluster.Autoscaling
+		if autoscaling == nil {
+			continue
+		}
 
-		if service.Resources.Limits.Cpu == "" {
-			service.Resources.Limits.Cpu = s.LimitsCPUDefault
+		if cluster.Replicas > 0 {
+			return fmt.Errorf("service '%s' can't have both replicas and autoscaling on cluster '%s'", service.Name, cluster.Cluster)
+		}
+		if autoscaling.Kind != "hpa" && autoscaling.Kind != "keda" {
+			return fmt.Errorf("service '%s' has invalid autoscaling kind '%s' (hpa, keda)", service.Name, autoscaling.Kind)
 		}
-		if service.Resources.Requests.Cpu == "" {
-			service.Resources.Requests.Cpu = s.RequestsCPUDefault
+		if autoscaling.MinReplicas < 1 {
+			return fmt.Errorf("service '%s' has invalid minimum number of replicas on cluster '%s'", service.Name, cluster.Cluster)
 		}
-		if service.Resources.Limits.Memory == "" {
-			service.Resources.Limits.Memory = s.LimitsMemoryDefault
+		if autoscaling.MaxReplicas < 1 || autoscaling.MaxReplicas < autoscaling.MinReplicas {
+			return fmt.Errorf("service '%s' has invalid maximum number of replicas on cluster '%s'", service.Name, cluster.Cluster)
 		}
-		if service.Resources.Requests.Memory == "" {
-			service.Resources.Requests.Memory = s.RequestsMemoryDefault
+		if autoscaling.Cpu < 0 || autoscaling.Memory < 0 {
+			return fmt.Errorf("service '%s' has negative autoscaling utilization on cluster '%s'", service.Name, cluster.Cluster)
+		}
+		// Utilization is relative to the requests
+		if (autoscaling.Cpu > 0 && service.Resources.Requests.Cpu == "") || (autoscaling.Memory > 0 && service.Resources.Requests.Memory == "") {
+			return fmt.Errorf("service '%s' needs resource requests to scale on their utilization", service.Name)
 		}
 
-		if service.ReadinessProbe <= 0 {
-			service.ReadinessProbe = s.SvcReadinessProbeDefault
+		if metric := autoscaling.Metric; metric != nil {
+			if _, err := resource.ParseQuantity(metric.Target); err != nil {
+				return fmt.Errorf("service '%s' has invalid autoscaling metric target '%s': %s", service.Name, metric.Target, err)
+			}
+			if autoscaling.Kind == "hpa" && metric.Name == "" {
+				return fmt.Errorf("service '%s' needs the name of the autoscaling metric", service.Name)
+			}
+			if autoscaling.Kind == "keda" && metric.Query == "" {
+				return fmt.Errorf("service '%s' needs a Prometheus query for the autoscaling metric", service.Name)
+			}
 		}
+	}
 
-		for j := range service.Clusters {
-			cluster := &service.Clusters[j]
-			if cluster.Namespace == "" {
-				cluster.Namespace = s.ClusterNamespaceDefault
+	return nil
+}
+
+// Validate that the vertical scaling of a service has valid bounds and does not compete with horizontal scaling
+func ValidateVerticalScaling(service *model.Service) error {
+	verticalScaling := service.VerticalScaling
+	if verticalScaling == nil {
+		return nil
+	}
+
+	validModes := map[string]bool{"Off": true, "Initial": true, "Recreate": true, "Auto": true}
+	validControlledValues := map[string]bool{"RequestsAndLimits": true, "RequestsOnly": true}
+
+	if !validModes[verticalScaling.Mode] {
+		return fmt.Errorf("service '%s' has invalid vertical scaling mode '%s' (Off, Initial, Recreate, Auto)", service.Name, verticalScaling.Mode)
+	}
+	if !validControlledValues[verticalScaling.ControlledValues] {
+		return fmt.Errorf("service '%s' has invalid vertical scaling controlled values '%s' (RequestsAndLimits, RequestsOnly)",
+			service.Name, verticalScaling.ControlledValues)
+	}
+
+	bounds := []struct {
+		resource string
+		min, max string
+	}{
+		{"CPU", verticalScaling.MinCpu, verticalScaling.MaxCpu},
+		{"memory", verticalScaling.MinMemory, verticalScaling.MaxMemory},
+	}
+	for _, bound := range bounds {
+		for _, quantity := range []string{bound.min, bound.max} {
+			if _, err := resource.ParseQuantity(quantity); quantity != "" && err != nil {
+				return fmt.Errorf("service '%s' has invalid vertical scaling bound '%s': %s", service.Name, quantity, err)
+			}
+		}
+		if bound.min != "" && bound.max != "" && compareQuantities(bound.min, bound.max) > 0 {
+			return fmt.Errorf("service '%s' has minimum %s '%s' greater than its maximum '%s'", service.Name, bound.resource, bound.min, bound.max)
+		}
+	}
+
+	// Both autoscalers would react to the same utilization
+	if verticalScaling.Mode != "Off" {
+		for _, cluster := range service.Clusters {
+			if cluster.Autoscaling != nil && (cluster.Autoscaling.Cpu > 0 || cluster.Autoscaling.Memory > 0) {
+				return fmt.Errorf("service '%s' can't scale vertically and horizontally on CPU or memory on cluster '%s'", service.Name, cluster.Cluster)
 			}
 		}
+	}
 
-		for k := range service.Endpoints {
-			endpoint := &service.Endpoints[k]
+	return nil
+}
 
-			if endpoint.ExecutionMode == "" {
-				endpoint.ExecutionMode = "sequential"
+// Validates the probes of a service and the ways its health checks fail
+func ValidateProbes(service *model.Service) error {
+	// Probes are optional until the defaults add the readiness probe
+	if service.Probes != nil {
+		probes := map[string]*model.Probe{
+			"readiness": service.Probes.Readiness,
+			"liveness":  service.Probes.Liveness,
+			"startup":   service.Probes.Startup,
+		}
+		for name, probe := range probes {
+			if probe == nil {
+				continue
 			}
-			if endpoint.CpuComplexity != nil && endpoint.CpuComplexity.Threads < 1 {
-				endpoint.CpuComplexity.Threads = 1
+			if probe.InitialDelay < 0 || probe.Period < 0 || probe.Timeout < 0 || probe.FailureThreshold < 0 || probe.SuccessThreshold < 0 {
+				return fmt.Errorf("%s probe of service '%s' has negative values", name, service.Name)
 			}
-			if endpoint.NetworkComplexity != nil {
-				if endpoint.NetworkComplexity.ForwardRequests == "" {
-					endpoint.NetworkComplexity.ForwardRequests = "synchronous"
-				}
-				for l := range endpoint.NetworkComplexity.CalledServices {
-					calledService := &endpoint.NetworkComplexity.CalledServices[l]
+			// Kubernetes only allows a success threshold of 1 for other probes
+			if name != "readiness" && probe.SuccessThreshold > 1 {
+				return fmt.Errorf("%s probe of service '%s' can't have a success threshold", name, service.Name)
+			}
+		}
+	}
 
-					if calledService.TrafficForwardRatio < 1 {
-						calledService.TrafficForwardRatio = 1
-					}
-					if calledService.Port == 0 {
-						calledService.Port = s.DefaultExtPort
-					}
-					if calledService.Protocol == "" {
-						for _, potentialCalledService := range config.Services {
-							if potentialCalledService.Name == calledService.Service {
-								// No need to check endpoints since services can't have duplicate names
-								calledService.Protocol = potentialCalledService.Protocol
-							}
-						}
-
-						// Assume HTTP if service was not found
-						if calledService.Protocol == "" {
-							calledService.Protocol = "http"
-						}
-					}
-				}
+	health := service.Health
+	if health == nil {
+		return nil
+	}
+	if health.StartupDelay < 0 || health.MaxInFlight < 0 || health.UnreadyAfter < 0 || health.UnhealthyAfter < 0 {
+		return fmt.Errorf("health of service '%s' has negative values", service.Name)
+	}
+	if health.UnhealthyAfter > 0 && (service.Probes == nil || service.Probes.Liveness == nil) {
+		return fmt.Errorf("service '%s' becomes unhealthy after %d requests, but has no liveness probe", service.Name, health.UnhealthyAfter)
+	}
+
+	return nil
+}
+
+// Validates that a number of pods is a non-negative integer or a percentage
+func validateIntOrPercent(value, name, serviceName string) error {
+	if !regexp.MustCompile(`^[0-9]+%?$`).MatchString(value) {
+		return fmt.Errorf("%s of service '%s' must be a number of pods or a percentage, got '%s'", name, serviceName, value)
+	}
+	return nil
+}
+
+// Validates the rollout settings of a service
+func ValidateRollout(service *model.Service) error {
+	if rollout := service.Rollout; rollout != nil {
+		values := map[string]string{"max unavailable": rollout.MaxUnavailable, "max surge": rollout.MaxSurge}
+		if budget := rollout.DisruptionBudget; budget != nil {
+			if (budget.MinAvailable == "") == (budget.MaxUnavailable == "") {
+				return fmt.Errorf("disruption budget of service '%s' needs either min available or max unavailable", service.Name)
+			}
+			values["disruption budget min available"] = budget.MinAvailable
+			values["disruption budget max unavailable"] = budget.MaxUnavailable
+		}
+		for name, value := range values {
+			if value == "" {
+				continue
+			}
+			if err := validateIntOrPercent(value, name, service.Name); err != nil {
+				return err
+			}
+		}
+
+		if service.Storage != nil && rollout.MaxSurge != "" {
+			return fmt.Errorf("service '%s' has storage, but StatefulSets can't have a max surge", service.Name)
+		}
+		if strings.TrimSuffix(rollout.MaxUnavailable, "%") == "0" && strings.TrimSuffix(rollout.MaxSurge, "%") == "0" {
+			return fmt.Errorf("max unavailable and max surge of service '%s' can't both be 0", service.Name)
+		}
+	}
+
+	return nil
+}
+
+// Validates the versions of a service
+// All versions need the same endpoints as the service, since they are reached through the same Kubernetes service
+func ValidateVersions(service *model.Service) error {
+	if len(service.Versions) == 0 {
+		return nil
+	}
+
+	endpoints := []string{}
+	for _, endpoint := range service.Endpoints {
+		endpoints = append(endpoints, endpoint.Name)
+	}
+	sort.Strings(endpoints)
+
+	// Calls of a version are only allowed to the services called by the service, which the manifests are generated for
+	called := map[string]bool{}
+	for _, name := range calledServices(*service) {
+		called[name] = true
+	}
+
+	names := map[string]bool{}
+	weights := 0
+	for _, version := range service.Versions {
+		if errs := validation.IsDNS1035Label(version.Name); len(errs) > 0 {
+			return fmt.Errorf("version '%s' of service '%s' has invalid name: %s", version.Name, service.Name, errs[0])
+		}
+		if errs := validation.IsDNS1035Label(service.Name + "-" + version.Name); len(errs) > 0 {
+			return fmt.Errorf("version '%s' of service '%s' results in invalid name '%s-%s': %s", version.Name, service.Name, service.Name, version.Name, errs[0])
+		}
+		if names[version.Name] {
+			return fmt.Errorf("duplicate version '%s' in service '%s'", version.Name, service.Name)
+		}
+		names[version.Name] = true
+
+		if version.Replicas < 0 {
+			return fmt.Errorf("version '%s' of service '%s' has a negative number of replicas", version.Name, service.Name)
+		}
+		if version.Weight < 0 {
+			return fmt.Errorf("version '%s' of service '%s' has a negative weight", version.Name, service.Name)
+		}
+		weights += version.Weight
+
+		versionEndpoints := []string{}
+		for _, endpoint := range version.Endpoints {
+			versionEndpoints = append(versionEndpoints, endpoint.Name)
+		}
+		sort.Strings(versionEndpoints)
+		if strings.Join(endpoints, ",") != strings.Join(versionEndpoints, ",") {
+			return fmt.Errorf("version '%s' of service '%s' needs the same endpoints as the service", version.Name, service.Name)
+		}
+
+		v := *service
+		v.Endpoints = version.Endpoints
+		for _, name := range calledServices(v) {
+			if !called[name] {
+				return fmt.Errorf("version '%s' of service '%s' calls service '%s', which the service doesn't call", version.Name, service.Name, name)
+			}
+		}
+		if err := validateEndpoints(&v); err != nil {
+			return err
+		}
+	}
+
+	if weighted(*service) && weights != 100 {
+		return fmt.Errorf("weights of the versions of service '%s' must sum up to 100, got %d", service.Name, weights)
+	}
+	return nil
+}
+
+// Validate that input JSON contains required parameters
+func ValidateRequiredParameters(config *model.FileConfig) error {
+	if len(config.Services) == 0 {
+		return errors.New("at least one service is required")
+	}
+
+	for _, service := range config.Services {
+		if len(service.Clusters) == 0 {
+			return fmt.Errorf("service '%s' needs to be deployed on at least one cluster", service.Name)
+		}
+		if service.Processes < 0 {
+			return fmt.Errorf("service '%s' has invalid number of processes (0 = auto, >0 = manual)", service.Name)
+		}
+
+		if len(service.Endpoints) == 0 {
+			return fmt.Errorf("at least one endpoint is required in service '%s'", service.Name)
+		} else {
+			err := validateEndpoints(&service)
+			if err != nil {
+				return err
+			}
+			err = ValidateAutoscaling(&service)
+			if err != nil {
+				return err
+			}
+			err = ValidateVerticalScaling(&service)
+			if err != nil {
+				return err
+			}
+			err = ValidateProbes(&service)
+			if err != nil {
+				return err
+			}
+			err = ValidateRollout(&service)
+			if err != nil {
+				return err
+			}
+			err = ValidateVersions(&service)
+			if err != nil {
+				return err
+			}
+			// Without a mesh, weights are only applied by an implementation of the GAMMA initiative, which the cluster must have
+			if weighted(service) && config.Mesh == nil && !config.Settings.Gamma {
+				return fmt.Errorf("weights of the versions of service '%s' need a service mesh or the gamma setting", service.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validates the endpoints of a service
+func validateEndpoints(service *model.Service) error {
+	err := ValidateProtocols(service)
+	if err != nil {
+		return err
+	}
+	err = ValidateHedging(service)
+	if err != nil {
+		return err
+	}
+	err = ValidateSteps(service)
+	if err != nil {
+		return err
+	}
+	err = ValidateCpuModes(service)
+	if err != nil {
+		return err
+	}
+	err = ValidateLocks(service)
+	if err != nil {
+		return err
+	}
+	err = ValidatePayloads(service)
+	if err != nil {
+		return err
+	}
+	return ValidateStorage(service)
+}
+
+// Validates the settings section in input JSON
+func ValidateSettings(config *model.FileConfig) error {
+	if config.Settings.DeadlineReduction < 0 {
+		return fmt.Errorf("deadline reduction can't be negative")
+	}
+	if config.Settings.Gamma && config.Mesh != nil {
+		return fmt.Errorf("gamma setting is only used without a service mesh")
+	}
+
+	return nil
+}
+
+// Validates the discovery settings and that every called service can be reached from every cluster where its callers run
+func ValidateDiscovery(config *model.FileConfig) error {
+	if discovery := config.Settings.Discovery; discovery != nil {
+		if discovery.Method != s.DiscoveryMcs && discovery.Method != s.DiscoveryExternal {
+			return fmt.Errorf("invalid discovery method '%s'", discovery.Method)
+		}
+		for name, address := range discovery.Addresses {
+			if errs := validation.IsDNS1035Label(name); len(errs) > 0 {
+				return fmt.Errorf("discovery address for invalid service '%s': %s", name, errs[0])
+			}
+			if errs := validation.IsDNS1123Subdomain(address); len(errs) > 0 {
+				return fmt.Errorf("discovery address of service '%s' has invalid name '%s': %s", name, address, errs[0])
+			}
+		}
+	}
+
+	for _, service := range config.Services {
+		for _, cluster := range service.Clusters {
+			for _, name := range calledServices(service) {
+				if _, err := discoveryHost(*config, name, cluster.Cluster, cluster.Namespace); err != nil {
+					return fmt.Errorf("service '%s' on cluster '%s' can't reach service '%s': %s", service.Name, cluster.Cluster, name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validates the workload section in input JSON
+func ValidateWorkload(config *model.FileConfig) error {
+	workload := config.Workload
+	if workload == nil {
+		return nil
+	}
+
+	if workload.Kind != "job" && workload.Kind != "deployment" {
+		return fmt.Errorf("workload has invalid kind '%s' (job, deployment)", workload.Kind)
+	}
+	if len(workload.Endpoints) == 0 {
+		return errors.New("workload needs at least one endpoint")
+	}
+	for _, workloadEndpoint := range workload.Endpoints {
+		name := workloadEndpoint.Service + "/" + workloadEndpoint.Endpoint
+		if workloadEndpoint.Weight <= 0 {
+			return fmt.Errorf("workload endpoint '%s' must have a positive weight", name)
+		}
+
+		found, deployed := false, false
+		for _, service := range config.Services {
+			if service.Name != workloadEndpoint.Service {
+				continue
+			}
+			for _, endpoint := range service.Endpoints {
+				found = found || endpoint.Name == workloadEndpoint.Endpoint
+			}
+			for _, cluster := range service.Clusters {
+				deployed = deployed || cluster.Cluster == workload.Cluster
+			}
+		}
+		if !found {
+			return fmt.Errorf("workload endpoint '%s' does not exist", name)
+		}
+		// Requests are sent to the Kubernetes service in the cluster of the load generator
+		if !deployed {
+			return fmt.Errorf("workload endpoint '%s' is not deployed on cluster '%s'", name, workload.Cluster)
+		}
+	}
+
+	switch workload.Pattern {
+	case "closed":
+		if workload.Users < 1 {
+			return errors.New("workload pattern 'closed' needs at least one user")
+		}
+	case "constant", "poisson", "ramp":
+		if workload.Rate <= 0 {
+			return fmt.Errorf("workload pattern '%s' needs a positive rate", workload.Pattern)
+		}
+	case "step":
+		if workload.Rate <= 0 || workload.StepDuration <= 0 {
+			return errors.New("workload pattern 'step' needs a positive rate and step duration")
+		}
+	case "trace":
+		if len(workload.Trace) == 0 {
+			return errors.New("workload pattern 'trace' needs at least one arrival in the trace")
+		}
+		for _, arrival := range workload.Trace {
+			if arrival < 0 {
+				return errors.New("workload trace can't contain negative arrival times")
+			}
+		}
+	default:
+		return fmt.Errorf("workload has invalid pattern '%s' (closed, constant, poisson, step, ramp, trace)", workload.Pattern)
+	}
+
+	if workload.Duration <= 0 {
+		return errors.New("workload duration must be positive")
+	}
+	if workload.ThinkTime < 0 || workload.EndRate < 0 || workload.PayloadSize < 0 {
+		return errors.New("workload think time, end rate and payload size can't be negative")
+	}
+	if workload.MaxInFlight < 1 {
+		return errors.New("workload must allow at least one request in flight")
+	}
+	if workload.Timeout <= 0 || workload.Interval <= 0 {
+		return errors.New("workload timeout and interval must be positive")
+	}
+	if workload.Format != "csv" && workload.Format != "json" {
+		return fmt.Errorf("workload has invalid format '%s' (csv, json)", workload.Format)
+	}
+
+	return nil
+}
+
+// Validates the scheduling constraints of every service on every cluster
+func ValidateScheduling(config *model.FileConfig) error {
+	validNodeOperators := map[string]bool{"In": true, "NotIn": true, "Exists": true, "DoesNotExist": true, "Gt": true, "Lt": true}
+	validTolerationOperators := map[string]bool{"Equal": true, "Exists": true}
+	validEffects := map[string]bool{"": true, "NoSchedule": true, "PreferNoSchedule": true, "NoExecute": true}
+	validWhenUnsatisfiable := map[string]bool{"DoNotSchedule": true, "ScheduleAnyway": true}
+
+	for _, service := range config.Services {
+		for _, cluster := range service.Clusters {
+			where := fmt.Sprintf("service '%s' on cluster '%s'", service.Name, cluster.Cluster)
+			namespaces := clusterNamespaces(*config, cluster.Cluster)
+
+			// The scheduler is bypassed for pods with a node name
+			if cluster.Node != "" && (len(cluster.NodeSelector) > 0 || len(cluster.NodeAffinity) > 0 || len(cluster.ServiceAffinity) > 0 ||
+				len(cluster.ServiceAntiAffinity) > 0 || len(cluster.TopologySpread) > 0) {
+				return fmt.Errorf("%s can't have both a node and scheduling constraints", where)
+			}
+
+			for _, rule := range cluster.NodeAffinity {
+				if !validNodeOperators[rule.Operator] {
+					return fmt.Errorf("%s has invalid node affinity operator '%s' (In, NotIn, Exists, DoesNotExist, Gt, Lt)", where, rule.Operator)
+				}
+				switch rule.Operator {
+				case "In", "NotIn":
+					if len(rule.Values) == 0 {
+						return fmt.Errorf("%s needs values for node affinity operator '%s'", where, rule.Operator)
+					}
+				case "Exists", "DoesNotExist":
+					if len(rule.Values) > 0 {
+						return fmt.Errorf("%s can't have values for node affinity operator '%s'", where, rule.Operator)
+					}
+				case "Gt", "Lt":
+					if len(rule.Values) != 1 {
+						return fmt.Errorf("%s needs one value for node affinity operator '%s'", where, rule.Operator)
+					}
+				}
+				if rule.Key == "" || rule.Weight < 0 || rule.Weight > 100 {
+					return fmt.Errorf("%s has node affinity without a key or with invalid weight %d (0-100)", where, rule.Weight)
+				}
+			}
+
+			for _, rule := range append(append([]model.ServiceAffinityRule{}, cluster.ServiceAffinity...), cluster.ServiceAntiAffinity...) {
+				if _, ok := namespaces[rule.Service]; !ok {
+					return fmt.Errorf("%s has affinity to service '%s', which is not deployed on the cluster", where, rule.Service)
+				}
+				if rule.Weight < 0 || rule.Weight > 100 {
+					return fmt.Errorf("%s has service affinity with invalid weight %d (0-100)", where, rule.Weight)
+				}
+			}
+
+			for _, toleration := range cluster.Tolerations {
+				if !validTolerationOperators[toleration.Operator] {
+					return fmt.Errorf("%s has invalid toleration operator '%s' (Equal, Exists)", where, toleration.Operator)
+				}
+				if !validEffects[toleration.Effect] {
+					return fmt.Errorf("%s has invalid toleration effect '%s' (NoSchedule, PreferNoSchedule, NoExecute)", where, toleration.Effect)
+				}
+				if toleration.Operator == "Exists" && toleration.Value != "" {
+					return fmt.Errorf("%s can't have a toleration value with operator 'Exists'", where)
+				}
+				if toleration.Key == "" && toleration.Operator != "Exists" {
+					return fmt.Errorf("%s needs operator 'Exists' to tolerate all taints", where)
+				}
+				if toleration.TolerationSeconds != nil && toleration.Effect != "NoExecute" {
+					return fmt.Errorf("%s can only set toleration seconds for effect 'NoExecute'", where)
+				}
+			}
+
+			for _, spread := range cluster.TopologySpread {
+				if spread.MaxSkew < 1 {
+					return fmt.Errorf("%s has invalid maximum topology skew %d", where, spread.MaxSkew)
+				}
+				if !validWhenUnsatisfiable[spread.WhenUnsatisfiable] {
+					return fmt.Errorf("%s has invalid topology spread '%s' (DoNotSchedule, ScheduleAnyway)", where, spread.WhenUnsatisfiable)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validates a mesh policy of the mesh section, name is the part of the section used in errors
+func validateMeshPolicy(policy *model.MeshPolicy, name string) error {
+	validMtlsModes := map[string]bool{"": true, "STRICT": true, "PERMISSIVE": true, "DISABLE": true}
+	validLoadBalancers := map[string]bool{"": true, "ROUND_ROBIN": true, "LEAST_REQUEST": true, "RANDOM": true, "PASSTHROUGH": true}
+
+	if !validMtlsModes[policy.Mtls] {
+		return fmt.Errorf("%s has invalid mTLS mode '%s' (STRICT, PERMISSIVE, DISABLE)", name, policy.Mtls)
+	}
+	if !validLoadBalancers[policy.LoadBalancer] {
+		return fmt.Errorf("%s has invalid load balancer '%s' (ROUND_ROBIN, LEAST_REQUEST, RANDOM, PASSTHROUGH)", name, policy.LoadBalancer)
+	}
+	if policy.Timeout < 0 {
+		return fmt.Errorf("%s has negative timeout", name)
+	}
+
+	if pool := policy.ConnectionPool; pool != nil {
+		if pool.MaxConnections < 0 || pool.MaxPendingRequests < 0 || pool.MaxRequests < 0 || pool.MaxRequestsPerConnection < 0 || pool.ConnectTimeout < 0 {
+			return fmt.Errorf("%s has negative connection pool settings", name)
+		}
+	}
+	if outlierDetection := policy.OutlierDetection; outlierDetection != nil {
+		if outlierDetection.ConsecutiveErrors < 0 || outlierDetection.Interval < 0 || outlierDetection.BaseEjectionTime < 0 {
+			return fmt.Errorf("%s has negative outlier detection settings", name)
+		}
+		if outlierDetection.MaxEjectionPercent < 0 || outlierDetection.MaxEjectionPercent > 100 {
+			return fmt.Errorf("%s has invalid maximum ejection percent %d (0-100)", name, outlierDetection.MaxEjectionPercent)
+		}
+	}
+	if retries := policy.Retries; retries != nil {
+		if retries.Attempts < 0 || retries.PerTryTimeout < 0 {
+			return fmt.Errorf("%s has negative retry settings", name)
+		}
+	}
+
+	return nil
+}
+
+// Validates the mesh section in input JSON
+func ValidateMesh(config *model.FileConfig) error {
+	mesh := config.Mesh
+	if mesh == nil {
+		return nil
+	}
+
+	if err := validateMeshPolicy(&mesh.MeshPolicy, "mesh"); err != nil {
+		return err
+	}
+
+	meshServices := []string{}
+	for _, meshService := range mesh.Services {
+		found := false
+		for _, service := range config.Services {
+			found = found || service.Name == meshService.Service
+		}
+		if !found {
+			return fmt.Errorf("mesh policy for service '%s' which does not exist", meshService.Service)
+		}
+		if err := validateMeshPolicy(&meshService.MeshPolicy, fmt.Sprintf("mesh policy for service '%s'", meshService.Service)); err != nil {
+			return err
+		}
+		meshServices = append(meshServices, meshService.Service)
+	}
+	for service, occurrences := range Occurrences(meshServices) {
+		if occurrences > 1 {
+			return fmt.Errorf("duplicate mesh policy for service '%s'", service)
+		}
+	}
+
+	return nil
+}
+
+// Validates the entry_points section in input JSON
+func ValidateEntryPoints(config *model.FileConfig) error {
+	entryPointsConfig := config.EntryPoints
+	if entryPointsConfig == nil {
+		return nil
+	}
+
+	kind := entryPointsConfig.Kind
+	if kind != "ingress" && kind != "gateway" && kind != "nodeport" && kind != "loadbalancer" {
+		return fmt.Errorf("invalid entry point kind '%s'", kind)
+	}
+	if entryPointsConfig.Class != "" && kind != "ingress" {
+		return fmt.Errorf("entry point class is only used by kind 'ingress'")
+	}
+	if (entryPointsConfig.Gateway != "" || entryPointsConfig.GatewayNamespace != "") && kind != "gateway" {
+		return fmt.Errorf("entry point gateway is only used by kind 'gateway'")
+	}
+	if entryPointsConfig.Host != "" && kind != "ingress" && kind != "gateway" {
+		return fmt.Errorf("entry point host is only used by kinds 'ingress' and 'gateway'")
+	}
+
+	if host := entryPointsConfig.Host; host != "" {
+		errs := validation.IsDNS1123Subdomain(host)
+		if strings.HasPrefix(host, "*.") {
+			errs = validation.IsWildcardDNS1123Subdomain(host)
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("entry point host '%s' is invalid: %s", host, errs[0])
+		}
+	}
+	if class := entryPointsConfig.Class; class != "" {
+		if errs := validation.IsDNS1123Subdomain(class); len(errs) > 0 {
+			return fmt.Errorf("entry point class '%s' is invalid: %s", class, errs[0])
+		}
+	}
+	if kind == "gateway" {
+		if errs := validation.IsDNS1123Subdomain(entryPointsConfig.Gateway); len(errs) > 0 {
+			return fmt.Errorf("entry point gateway '%s' is invalid: %s", entryPointsConfig.Gateway, errs[0])
+		}
+		if namespace := entryPointsConfig.GatewayNamespace; namespace != "" {
+			if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+				return fmt.Errorf("entry point gateway namespace '%s' is invalid: %s", namespace, errs[0])
+			}
+		}
+	}
+
+	// gRPC requests can't be routed by path with an Ingress that does not know the backend protocol
+	for _, service := range config.Services {
+		if kind == "ingress" && service.Protocol == "grpc" && len(entryPoints(service)) > 0 {
+			return fmt.Errorf("gRPC service '%s' has entry points, which need an entry point kind other than 'ingress'", service.Name)
+		}
+	}
+
+	// Ingresses and HTTP routes of all services share the host and match the endpoint name as path, so each path can only be claimed once per cluster
+	// gRPC routes match the service name as well and don't collide
+	if kind == "ingress" || kind == "gateway" {
+		pathOwners := make(map[string]map[string]string)
+		for _, service := range config.Services {
+			if service.Protocol == "grpc" {
+				continue
+			}
+			for _, cluster := range service.Clusters {
+				if pathOwners[cluster.Cluster] == nil {
+					pathOwners[cluster.Cluster] = make(map[string]string)
+				}
+				for _, name := range entryPoints(service) {
+					path := "/" + name
+					if owner, exists := pathOwners[cluster.Cluster][path]; exists && owner != service.Name {
+						return fmt.Errorf("entry point path '%s' of service '%s' on cluster '%s' is already used by service '%s'", path, service.Name, cluster.Cluster, owner)
+					}
+					pathOwners[cluster.Cluster][path] = service.Name
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validates an input JSON config provided by the user
+func ValidateFileConfig(config *model.FileConfig) error {
+	if err := ValidateSettings(config); err != nil {
+		return err
+	}
+	if err := ValidateRequiredParameters(config); err != nil {
+		return err
+	}
+	if err := ValidateNames(config); err != nil {
+		return err
+	}
+	if err := ValidateResources(config); err != nil {
+		return err
+	}
+	if err := ValidateScheduling(config); err != nil {
+		return err
+	}
+	if err := ValidateDiscovery(config); err != nil {
+		return err
+	}
+	if err := ValidateWorkload(config); err != nil {
+		return err
+	}
+	if err := ValidateMesh(config); err != nil {
+		return err
+	}
+	if err := ValidateEntryPoints(config); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Applies default values to input JSON
+func ApplyDefaults(config *model.FileConfig) {
+	if config.Settings.BaseImage == "" {
+		config.Settings.BaseImage = s.BaseImageDefault
+	}
+
+	for i := range config.Services {
+		service := &config.Services[i]
+
+		applyResourceDefaults(config, service)
+
+		if service.ReadinessProbe <= 0 {
+			service.ReadinessProbe = s.SvcReadinessProbeDefault
+		}
+		applyProbeDefaults(service)
+		if service.Storage != nil && service.Storage.Size == "" {
+			service.Storage.Size = s.SvcStorageSizeDefault
+		}
+
+		applyAutoscalingDefaults(service.Autoscaling)
+		applyVerticalScalingDefaults(service.VerticalScaling)
+		for j := range service.Clusters {
+			cluster := &service.Clusters[j]
+			if cluster.Namespace == "" {
+				cluster.Namespace = s.ClusterNamespaceDefault
+			}
+
+			// Autoscaling of the service applies to every cluster without a fixed number of replicas
+			if cluster.Autoscaling == nil && service.Autoscaling != nil && cluster.Replicas == 0 {
+				autoscaling := *service.Autoscaling
+				cluster.Autoscaling = &autoscaling
+			}
+			applyAutoscalingDefaults(cluster.Autoscaling)
+			applySchedulingDefaults(cluster)
+		}
+
+		applyEndpointDefaults(config, service.Endpoints)
+		// A version without endpoints runs the endpoints of the service
+		for j := range service.Versions {
+			version := &service.Versions[j]
+			if len(version.Endpoints) == 0 {
+				version.Endpoints = copyEndpoints(service.Endpoints)
+			}
+			applyEndpointDefaults(config, version.Endpoints)
+		}
+	}
+
+	applyWorkloadDefaults(config)
+	applyMeshDefaults(config)
+	applyEntryPointDefaults(config)
+}
+
+// Applies default values to the endpoints of a service
+func applyEndpointDefaults(config *model.FileConfig, endpoints []model.Endpoint) {
+	for k := range endpoints {
+		endpoint := &endpoints[k]
+
+		if endpoint.ExecutionMode == "" {
+			endpoint.ExecutionMode = "sequential"
+		}
+		applyPayloadDefaults(endpoint.PayloadComplexity)
+		applyCpuDefaults(endpoint.CpuComplexity)
+		applyLockDefaults(endpoint.LockComplexity)
+		applyStorageDefaults(endpoint.StorageComplexity)
+		applyNetworkDefaults(config, endpoint.NetworkComplexity, "synchronous")
+
+		// Calls within a step are made in parallel unless specified otherwise
+		for l := range endpoint.Steps {
+			step := &endpoint.Steps[l]
+			applyPayloadDefaults(step.PayloadComplexity)
+			applyCpuDefaults(step.CpuComplexity)
+			applyLockDefaults(step.LockComplexity)
+			applyStorageDefaults(step.StorageComplexity)
+			applyNetworkDefaults(config, step.NetworkComplexity, "asynchronous")
+		}
+	}
+}
+
+// Applies the resource profile, QoS class and default values to the resources of a service
+// Allocations set for the service take precedence over its profile
+func applyResourceDefaults(config *model.FileConfig, service *model.Service) {
+	resources := &service.Resources
+	if profile, ok := resourceProfiles(config)[service.Profile]; ok {
+		if resources.Limits.Cpu == "" {
+			resources.Limits.Cpu = profile.Limits.Cpu
+		}
+		if resources.Requests.Cpu == "" {
+			resources.Requests.Cpu = profile.Requests.Cpu
+		}
+		if resources.Limits.Memory == "" {
+			resources.Limits.Memory = profile.Limits.Memory
+		}
+		if resources.Requests.Memory == "" {
+			resources.Requests.Memory = profile.Requests.Memory
+		}
+	}
+
+	if service.QosClass == "BestEffort" {
+		return
+	}
+
+	// Guaranteed pods need requests equal to their limits, so a missing request or limit is set to the other one
+	if service.QosClass == "Guaranteed" {
+		allocations := []struct {
+			request, limit *string
+			limitDefault   string
+		}{
+			{&resources.Requests.Cpu, &resources.Limits.Cpu, s.LimitsCPUDefault},
+			{&resources.Requests.Memory, &resources.Limits.Memory, s.LimitsMemoryDefault},
+		}
+		for _, allocation := range allocations {
+			if *allocation.limit == "" {
+				*allocation.limit = *allocation.request
+			}
+			if *allocation.limit == "" {
+				*allocation.limit = allocation.limitDefault
+			}
+			if *allocation.request == "" {
+				*allocation.request = *allocation.limit
+			}
+		}
+	}
+
+	// Like Kubernetes, a missing request doesn't exceed the given limit, and a missing limit isn't below the given request
+	defaults := []struct {
+		request, limit               *string
+		requestDefault, limitDefault string
+	}{
+		{&resources.Requests.Cpu, &resources.Limits.Cpu, s.RequestsCPUDefault, s.LimitsCPUDefault},
+		{&resources.Requests.Memory, &resources.Limits.Memory, s.RequestsMemoryDefault, s.LimitsMemoryDefault},
+	}
+	for _, allocation := range defaults {
+		// Invalid quantities are left to validation
+		if *allocation.request == "" {
+			*allocation.request = allocation.requestDefault
+			if _, err := resource.ParseQuantity(*allocation.limit); err == nil && compareQuantities(*allocation.request, *allocation.limit) > 0 {
+				*allocation.request = *allocation.limit
+			}
+		}
+		if *allocation.limit == "" {
+			*allocation.limit = allocation.limitDefault
+			if _, err := resource.ParseQuantity(*allocation.request); err == nil && compareQuantities(*allocation.request, *allocation.limit) > 0 {
+				*allocation.limit = *allocation.request
+			}
+		}
+	}
+}
+
+// Applies default values to the scheduling constraints of a service on a cluster
+func applySchedulingDefaults(cluster *model.Cluster) {
+	for i := range cluster.ServiceAffinity {
+		if cluster.ServiceAffinity[i].TopologyKey == "" {
+			cluster.ServiceAffinity[i].TopologyKey = s.ClusterTopologyKeyDefault
+		}
+	}
+	for i := range cluster.ServiceAntiAffinity {
+		if cluster.ServiceAntiAffinity[i].TopologyKey == "" {
+			cluster.ServiceAntiAffinity[i].TopologyKey = s.ClusterTopologyKeyDefault
+		}
+	}
+	for i := range cluster.Tolerations {
+		if cluster.Tolerations[i].Operator == "" {
+			cluster.Tolerations[i].Operator = s.ClusterTolerationOperatorDefault
+		}
+	}
+	for i := range cluster.TopologySpread {
+		spread := &cluster.TopologySpread[i]
+		if spread.TopologyKey == "" {
+			spread.TopologyKey = s.ClusterTopologyKeyDefault
+		}
+		if spread.MaxSkew == 0 {
+			spread.MaxSkew = s.ClusterMaxSkewDefault
+		}
+		if spread.WhenUnsatisfiable == "" {
+			spread.WhenUnsatisfiable = s.ClusterWhenUnsatisfiableDefault
+		}
+	}
+}
+
+// The readiness probe is checked every second after the initial delay of the service, unless set otherwise
+func applyProbeDefaults(service *model.Service) {
+	if service.Probes == nil {
+		service.Probes = &model.Probes{}
+	}
+	if service.Probes.Readiness == nil {
+		service.Probes.Readiness = &model.Probe{}
+	}
+
+	readiness := service.Probes.Readiness
+	if readiness.InitialDelay == 0 {
+		readiness.InitialDelay = service.ReadinessProbe
+	}
+	if readiness.Period == 0 {
+		readiness.Period = s.SvcReadinessPeriodDefault
+	}
+}
+
+// Applies default values to the vertical scaling of a service
+func applyVerticalScalingDefaults(verticalScaling *model.VerticalScaling) {
+	if verticalScaling == nil {
+		return
+	}
+
+	if verticalScaling.Mode == "" {
+		verticalScaling.Mode = s.SvcVerticalScalingModeDefault
+	}
+	if verticalScaling.ControlledValues == "" {
+		verticalScaling.ControlledValues = s.SvcVerticalScalingControlledValuesDefault
+	}
+}
+
+// Applies default values to the autoscaling of a service or cluster
+func applyAutoscalingDefaults(autoscaling *model.Autoscaling) {
+	if autoscaling == nil {
+		return
+	}
+
+	if autoscaling.Kind == "" {
+		autoscaling.Kind = s.SvcAutoscalingKindDefault
+	}
+	if autoscaling.MinReplicas == 0 {
+		autoscaling.MinReplicas = s.SvcAutoscalingMinReplicasDefault
+	}
+	if autoscaling.Cpu == 0 && autoscaling.Memory == 0 && autoscaling.Metric == nil {
+		autoscaling.Cpu = s.SvcAutoscalingCpuDefault
+	}
+	if autoscaling.Kind == "keda" && autoscaling.PrometheusAddress == "" {
+		autoscaling.PrometheusAddress = s.SvcAutoscalingPrometheusDefault
+	}
+}
+
+// Applies default values to payload stressor parameters
+func applyPayloadDefaults(payloadComplexity *model.PayloadComplexity) {
+	if payloadComplexity == nil {
+		return
+	}
+
+	if payloadComplexity.ContentType == "" {
+		payloadComplexity.ContentType = s.EpPayloadContentTypeDefault
+	}
+	if payloadComplexity.Transform == "" {
+		payloadComplexity.Transform = s.EpPayloadTransformDefault
+	}
+}
+
+// Applies default values to CPU stressor parameters
+func applyCpuDefaults(cpuComplexity *model.CpuComplexity) {
+	if cpuComplexity == nil {
+		return
+	}
+
+	if cpuComplexity.Mode == "" {
+		cpuComplexity.Mode = s.EpCpuModeDefault
+	}
+	if cpuComplexity.Mode == "time" {
+		if cpuComplexity.Kernel == "" {
+			cpuComplexity.Kernel = s.EpCpuKernelDefault
+		}
+		// The busy loop has no working set
+		if cpuComplexity.WorkingSetSize == 0 && cpuComplexity.Kernel != "busy" {
+			cpuComplexity.WorkingSetSize = s.EpCpuWorkingSetSizeDefault
+		}
+	}
+	if cpuComplexity.Threads < 1 {
+		cpuComplexity.Threads = 1
+	}
+}
+
+// Applies default values to lock stressor parameters
+func applyLockDefaults(lockComplexity *model.LockComplexity) {
+	if lockComplexity == nil {
+		return
+	}
+
+	if lockComplexity.Locks == 0 {
+		lockComplexity.Locks = s.EpLockLocksDefault
+	}
+	if lockComplexity.HoldMode == "" {
+		lockComplexity.HoldMode = s.EpLockHoldModeDefault
+	}
+}
+
+// Applies default values to storage stressor parameters
+func applyStorageDefaults(storageComplexity *model.StorageComplexity) {
+	if storageComplexity == nil {
+		return
+	}
+
+	if storageComplexity.Operations == 0 {
+		storageComplexity.Operations = s.EpStorageOperationsDefault
+	}
+	if storageComplexity.Distribution == "" {
+		storageComplexity.Distribution = s.EpStorageDistributionDefault
+	}
+	if storageComplexity.Distribution == "zipf" && storageComplexity.ZipfExponent == 0 {
+		storageComplexity.ZipfExponent = s.EpStorageZipfExponentDefault
+	}
+}
+
+// Applies default values to the workload section, after the defaults of the services
+func applyWorkloadDefaults(config *model.FileConfig) {
+	workload := config.Workload
+	if workload == nil {
+		return
+	}
+
+	if workload.Kind == "" {
+		workload.Kind = s.WlKindDefault
+	}
+	if workload.Pattern == "" {
+		workload.Pattern = s.WlPatternDefault
+	}
+	if workload.Duration == 0 {
+		workload.Duration = s.WlDurationDefault
+	}
+	if workload.Pattern == "closed" && workload.Users == 0 {
+		workload.Users = s.WlUsersDefault
+	}
+	if workload.Pattern != "closed" && workload.Pattern != "trace" && workload.Rate == 0 {
+		workload.Rate = s.WlRateDefault
+	}
+	if workload.Pattern == "step" && workload.StepDuration == 0 {
+		workload.StepDuration = s.WlStepDurationDefault
+	}
+	if workload.MaxInFlight == 0 {
+		workload.MaxInFlight = s.WlMaxInFlightDefault
+	}
+	if workload.Timeout == 0 {
+		workload.Timeout = s.WlTimeoutDefault
+	}
+	if workload.Interval == 0 {
+		workload.Interval = s.WlIntervalDefault
+	}
+	if workload.Format == "" {
+		workload.Format = s.WlFormatDefault
+	}
+
+	for i := range workload.Endpoints {
+		if workload.Endpoints[i].Weight == 0 {
+			workload.Endpoints[i].Weight = s.WlWeightDefault
+		}
+	}
+
+	// The load generator runs next to the first target endpoint unless specified otherwise
+	if len(workload.Endpoints) > 0 {
+		for _, service := range config.Services {
+			if service.Name != workload.Endpoints[0].Service || len(service.Clusters) == 0 {
+				continue
+			}
+			if workload.Cluster == "" {
+				workload.Cluster = service.Clusters[0].Cluster
+			}
+			for _, cluster := range service.Clusters {
+				if workload.Namespace == "" && cluster.Cluster == workload.Cluster {
+					workload.Namespace = cluster.Namespace
+				}
+			}
+		}
+	}
+	if workload.Namespace == "" {
+		workload.Namespace = s.ClusterNamespaceDefault
+	}
+}
+
+// Entry point endpoints are exposed with an Ingress if the entry_points section is left out
+func applyEntryPointDefaults(config *model.FileConfig) {
+	for _, service := range config.Services {
+		if config.EntryPoints == nil && len(entryPoints(service)) > 0 {
+			config.EntryPoints = &model.EntryPoints{}
+		}
+	}
+	if config.EntryPoints == nil {
+		return
+	}
+
+	if config.EntryPoints.Kind == "" {
+		config.EntryPoints.Kind = s.EntryPointKindDefault
+	}
+}
+
+// Applies default values to the mesh section
+func applyMeshDefaults(config *model.FileConfig) {
+	if config.Mesh == nil {
+		return
+	}
+
+	if config.Mesh.Mtls == "" {
+		config.Mesh.Mtls = s.MeshMtlsDefault
+	}
+}
+
+// Applies default values to network stressor parameters and the endpoint calls it makes
+func applyNetworkDefaults(config *model.FileConfig, networkComplexity *model.NetworkComplexity, forwardRequests string) {
+	if networkComplexity == nil {
+		return
+	}
+
+	if networkComplexity.ForwardRequests == "" {
+		networkComplexity.ForwardRequests = forwardRequests
+	}
+	if networkComplexity.ResponsePayloadContent == "" {
+		networkComplexity.ResponsePayloadContent = s.EpPayloadContentTypeDefault
+	}
+	for i := range networkComplexity.CalledServices {
+		calledService := &networkComplexity.CalledServices[i]
+
+		if calledService.TrafficForwardRatio < 1 {
+			calledService.TrafficForwardRatio = 1
+		}
+		if calledService.Port == 0 {
+			calledService.Port = s.DefaultExtPort
+		}
+		if calledService.RequestPayloadContent == "" {
+			calledService.RequestPayloadContent = s.EpPayloadContentTypeDefault
+		}
+		if calledService.Hedging != nil && calledService.Hedging.MaxHedges == 0 {
+			calledService.Hedging.MaxHedges = s.CsMaxHedgesDefault
+		}
+		if calledService.Protocol == "" {
+			for _, potentialCalledService := range config.Services {
+				if potentialCalledService.Name == calledService.Service {
+					// No need to check endpoints since services can't have duplicate names
+					calledService.Protocol = potentialCalledService.Protocol
+				}
+			}
+
+			// Assume HTTP if service was not found
+			if calledService.Protocol == "" {
+				calledService.Protocol = "http"
 			}
 		}
 	}
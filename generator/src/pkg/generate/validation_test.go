@@ -0,0 +1,620 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	s "application-generator/src/pkg/service"
+	model "application-model"
+
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// Two services on one cluster, where frontend calls backend
+const testDescription = `{
+  "services": [
+    {
+      "name": "frontend",
+      "protocol": "http",
+      "clusters": [{"cluster": "cluster1", "namespace": "default"}],
+      "endpoints": [
+        {
+          "name": "endpoint1",
+          "network_complexity": {
+            "response_payload_size": 512,
+            "called_services": [{"service": "backend", "endpoint": "endpoint1", "request_payload_size": 256}]
+          }
+        }
+      ]
+    },
+    {
+      "name": "backend",
+      "protocol": "http",
+      "clusters": [{"cluster": "cluster1", "namespace": "default"}],
+      "endpoints": [{"name": "endpoint1", "cpu_complexity": {"execution_time": 0.001}}]
+    }
+  ]
+}`
+
+// Loads the test description like Load, after modify changed it, and returns it with the result of its validation
+func loadTestDescription(t *testing.T, modify func(config *model.FileConfig)) (model.FileConfig, error) {
+	t.Helper()
+
+	config := s.CreateFileConfig()
+	decoder := json.NewDecoder(strings.NewReader(testDescription))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	if modify != nil {
+		modify(&config)
+	}
+	ApplyDefaults(&config)
+	return config, ValidateFileConfig(&config)
+}
+
+// Returns the call from frontend to backend in the test description
+func testCall(config *model.FileConfig) *model.CalledService {
+	return &config.Services[0].Endpoints[0].NetworkComplexity.CalledServices[0]
+}
+
+// Returns the endpoint of backend in the test description
+func testEndpoint(config *model.FileConfig) *model.Endpoint {
+	return &config.Services[1].Endpoints[0]
+}
+
+// Returns a workload that sends requests to frontend in the test description
+func testWorkload() *model.Workload {
+	return &model.Workload{Endpoints: []model.WorkloadEndpoint{{Service: "frontend", Endpoint: "endpoint1"}}}
+}
+
+func TestValidateFileConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(config *model.FileConfig)
+		err    string
+	}{
+		{"valid", nil, ""},
+
+		{"hedging with delay", func(config *model.FileConfig) {
+			testCall(config).Hedging = &model.HedgingPolicy{Delay: 0.01}
+		}, ""},
+		{"hedging with percentile", func(config *model.FileConfig) {
+			testCall(config).Hedging = &model.HedgingPolicy{Percentile: 95, MaxHedges: 2}
+		}, ""},
+		{"hedging without delay or percentile", func(config *model.FileConfig) {
+			testCall(config).Hedging = &model.HedgingPolicy{}
+		}, "needs a hedging delay or percentile"},
+		{"negative hedging delay", func(config *model.FileConfig) {
+			testCall(config).Hedging = &model.HedgingPolicy{Delay: -1}
+		}, "negative hedging delay"},
+		{"hedging percentile of 100", func(config *model.FileConfig) {
+			testCall(config).Hedging = &model.HedgingPolicy{Percentile: 100}
+		}, "invalid hedging percentile"},
+		{"negative number of hedges", func(config *model.FileConfig) {
+			testCall(config).Hedging = &model.HedgingPolicy{Delay: 0.01, MaxHedges: -1}
+		}, "invalid number of hedges"},
+
+		{"steps", func(config *model.FileConfig) {
+			endpoint := testEndpoint(config)
+			endpoint.Steps = []model.Step{{CpuComplexity: endpoint.CpuComplexity}, {LockComplexity: &model.LockComplexity{HoldTime: 0.001}}}
+			endpoint.CpuComplexity = nil
+		}, ""},
+		{"steps and stressors", func(config *model.FileConfig) {
+			endpoint := testEndpoint(config)
+			endpoint.Steps = []model.Step{{LockComplexity: &model.LockComplexity{HoldTime: 0.001}}}
+		}, "both in steps and outside of steps"},
+		{"empty step", func(config *model.FileConfig) {
+			endpoint := testEndpoint(config)
+			endpoint.Steps = []model.Step{{CpuComplexity: endpoint.CpuComplexity}, {}}
+			endpoint.CpuComplexity = nil
+		}, "step 2 of endpoint 'endpoint1' in service 'backend' has no stressors"},
+
+		{"deadline reduction", func(config *model.FileConfig) {
+			config.Settings.DeadlineReduction = 0.01
+		}, ""},
+		{"negative deadline reduction", func(config *model.FileConfig) {
+			config.Settings.DeadlineReduction = -0.01
+		}, "deadline reduction can't be negative"},
+
+		{"work units", func(config *model.FileConfig) {
+			testEndpoint(config).CpuComplexity = &model.CpuComplexity{Mode: "work", WorkUnits: 10}
+		}, ""},
+		{"no work units", func(config *model.FileConfig) {
+			testEndpoint(config).CpuComplexity = &model.CpuComplexity{Mode: "work"}
+		}, "needs a positive number of CPU work units"},
+		{"negative execution time", func(config *model.FileConfig) {
+			testEndpoint(config).CpuComplexity.ExecutionTime = -1
+		}, "negative CPU execution time"},
+		{"invalid CPU mode", func(config *model.FileConfig) {
+			testEndpoint(config).CpuComplexity.Mode = "cycles"
+		}, "invalid CPU mode 'cycles'"},
+		{"utilization", func(config *model.FileConfig) {
+			testEndpoint(config).CpuComplexity = &model.CpuComplexity{Mode: "utilization", Utilization: 0.5}
+		}, ""},
+		{"utilization above 1", func(config *model.FileConfig) {
+			testEndpoint(config).CpuComplexity = &model.CpuComplexity{Mode: "utilization", Utilization: 1.5}
+		}, "invalid CPU utilization 1.5"},
+		{"utilization in steps", func(config *model.FileConfig) {
+			endpoint := testEndpoint(config)
+			endpoint.Steps = []model.Step{{CpuComplexity: &model.CpuComplexity{Mode: "utilization", Utilization: 0.5}}}
+			endpoint.CpuComplexity = nil
+		}, "can't use CPU utilization in steps"},
+		{"same utilization on several endpoints", func(config *model.FileConfig) {
+			service := &config.Services[1]
+			service.Endpoints[0].CpuComplexity = &model.CpuComplexity{Mode: "utilization", Utilization: 0.5}
+			service.Endpoints = append(service.Endpoints, model.Endpoint{Name: "endpoint2", CpuComplexity: &model.CpuComplexity{Mode: "utilization", Utilization: 0.5}})
+		}, ""},
+		{"different utilizations on several endpoints", func(config *model.FileConfig) {
+			service := &config.Services[1]
+			service.Endpoints[0].CpuComplexity = &model.CpuComplexity{Mode: "utilization", Utilization: 0.5}
+			service.Endpoints = append(service.Endpoints, model.Endpoint{Name: "endpoint2", CpuComplexity: &model.CpuComplexity{Mode: "utilization", Utilization: 0.2}})
+		}, "different CPU utilizations"},
+
+		{"CPU kernel", func(config *model.FileConfig) {
+			testEndpoint(config).CpuComplexity.Kernel = "matrix"
+		}, ""},
+		{"invalid CPU kernel", func(config *model.FileConfig) {
+			testEndpoint(config).CpuComplexity.Kernel = "fft"
+		}, "invalid CPU kernel 'fft'"},
+		{"CPU kernel in work mode", func(config *model.FileConfig) {
+			testEndpoint(config).CpuComplexity = &model.CpuComplexity{Mode: "work", WorkUnits: 10, Kernel: "sha256"}
+		}, "can only use CPU kernels in time mode"},
+		{"negative working set size", func(config *model.FileConfig) {
+			testEndpoint(config).CpuComplexity.WorkingSetSize = -1
+		}, "negative CPU working set size"},
+
+		{"locks", func(config *model.FileConfig) {
+			testEndpoint(config).LockComplexity = &model.LockComplexity{Locks: 4, HoldTime: 0.001, HoldMode: "sleep"}
+		}, ""},
+		{"negative number of locks", func(config *model.FileConfig) {
+			testEndpoint(config).LockComplexity = &model.LockComplexity{Locks: -1, HoldTime: 0.001}
+		}, "needs at least one lock"},
+		{"negative lock hold time", func(config *model.FileConfig) {
+			testEndpoint(config).LockComplexity = &model.LockComplexity{HoldTime: -1}
+		}, "negative lock hold time"},
+		{"invalid lock hold mode", func(config *model.FileConfig) {
+			testEndpoint(config).LockComplexity = &model.LockComplexity{HoldTime: 0.001, HoldMode: "spin"}
+		}, "invalid lock hold mode 'spin'"},
+
+		{"payload processing", func(config *model.FileConfig) {
+			testEndpoint(config).PayloadComplexity = &model.PayloadComplexity{ContentType: "json", Validate: true, Transform: "compress", CpuTimePerKb: 0.0001}
+		}, ""},
+		{"invalid payload content type", func(config *model.FileConfig) {
+			testEndpoint(config).PayloadComplexity = &model.PayloadComplexity{ContentType: "xml"}
+		}, "invalid payload content type 'xml'"},
+		{"invalid payload transform", func(config *model.FileConfig) {
+			testEndpoint(config).PayloadComplexity = &model.PayloadComplexity{Transform: "encrypt"}
+		}, "invalid payload transform 'encrypt'"},
+		{"negative payload CPU time", func(config *model.FileConfig) {
+			testEndpoint(config).PayloadComplexity = &model.PayloadComplexity{CpuTimePerKb: -1}
+		}, "negative payload CPU time"},
+		{"invalid response payload content", func(config *model.FileConfig) {
+			config.Services[0].Endpoints[0].NetworkComplexity.ResponsePayloadContent = "xml"
+		}, "invalid response payload content 'xml'"},
+		{"invalid request payload content", func(config *model.FileConfig) {
+			testCall(config).RequestPayloadContent = "xml"
+		}, "invalid request payload content 'xml'"},
+
+		{"storage", func(config *model.FileConfig) {
+			config.Services[1].Storage = &model.Storage{}
+			testEndpoint(config).StorageComplexity = &model.StorageComplexity{Keys: 100, ValueSize: 64, ReadRatio: 0.9, Distribution: "zipf", Persist: true}
+		}, ""},
+		{"invalid storage size", func(config *model.FileConfig) {
+			config.Services[1].Storage = &model.Storage{Size: "large"}
+		}, "invalid storage size 'large'"},
+		{"no storage keys", func(config *model.FileConfig) {
+			testEndpoint(config).StorageComplexity = &model.StorageComplexity{}
+		}, "needs at least one storage key"},
+		{"negative storage value size", func(config *model.FileConfig) {
+			testEndpoint(config).StorageComplexity = &model.StorageComplexity{Keys: 100, ValueSize: -1}
+		}, "negative storage value size"},
+		{"negative storage operations", func(config *model.FileConfig) {
+			testEndpoint(config).StorageComplexity = &model.StorageComplexity{Keys: 100, Operations: -1}
+		}, "needs at least one storage operation"},
+		{"storage read ratio above 1", func(config *model.FileConfig) {
+			testEndpoint(config).StorageComplexity = &model.StorageComplexity{Keys: 100, ReadRatio: 2}
+		}, "invalid storage read ratio 2"},
+		{"invalid storage key distribution", func(config *model.FileConfig) {
+			testEndpoint(config).StorageComplexity = &model.StorageComplexity{Keys: 100, Distribution: "normal"}
+		}, "invalid storage key distribution 'normal'"},
+		{"Zipf exponent of 1", func(config *model.FileConfig) {
+			testEndpoint(config).StorageComplexity = &model.StorageComplexity{Keys: 100, Distribution: "zipf", ZipfExponent: 1}
+		}, "invalid Zipf exponent 1"},
+		{"persisted storage without volume", func(config *model.FileConfig) {
+			testEndpoint(config).StorageComplexity = &model.StorageComplexity{Keys: 100, Persist: true}
+		}, "the service has no storage volume"},
+
+		{"workload", func(config *model.FileConfig) {
+			config.Workload = testWorkload()
+		}, ""},
+		{"workload with invalid kind", func(config *model.FileConfig) {
+			config.Workload = testWorkload()
+			config.Workload.Kind = "pod"
+		}, "workload has invalid kind 'pod'"},
+		{"workload without endpoints", func(config *model.FileConfig) {
+			config.Workload = &model.Workload{}
+		}, "workload needs at least one endpoint"},
+		{"workload with unknown endpoint", func(config *model.FileConfig) {
+			config.Workload = testWorkload()
+			config.Workload.Endpoints[0].Endpoint = "endpoint2"
+		}, "workload endpoint 'frontend/endpoint2' does not exist"},
+		{"workload on another cluster", func(config *model.FileConfig) {
+			config.Workload = testWorkload()
+			config.Workload.Cluster = "cluster2"
+		}, "workload endpoint 'frontend/endpoint1' is not deployed on cluster 'cluster2'"},
+		{"workload with invalid pattern", func(config *model.FileConfig) {
+			config.Workload = testWorkload()
+			config.Workload.Pattern = "burst"
+		}, "workload has invalid pattern 'burst'"},
+		{"workload trace with negative arrival", func(config *model.FileConfig) {
+			config.Workload = testWorkload()
+			config.Workload.Pattern = "trace"
+			config.Workload.Trace = []float64{0, -1}
+		}, "workload trace can't contain negative arrival times"},
+		{"workload with invalid format", func(config *model.FileConfig) {
+			config.Workload = testWorkload()
+			config.Workload.Format = "xml"
+		}, "workload has invalid format 'xml'"},
+		{"service named like the load generator", func(config *model.FileConfig) {
+			config.Services[1].Name = s.LoadGeneratorName
+			testCall(config).Service = s.LoadGeneratorName
+		}, "service name 'hydragen-loadgen' is reserved for the load generator"},
+
+		{"mesh", func(config *model.FileConfig) {
+			config.Mesh = &model.Mesh{
+				MeshPolicy: model.MeshPolicy{LoadBalancer: "LEAST_REQUEST", Retries: &model.Retries{Attempts: 3}},
+				Services:   []model.MeshService{{Service: "backend", MeshPolicy: model.MeshPolicy{Timeout: 1}}},
+			}
+		}, ""},
+		{"invalid mTLS mode", func(config *model.FileConfig) {
+			config.Mesh = &model.Mesh{MeshPolicy: model.MeshPolicy{Mtls: "OFF"}}
+		}, "mesh has invalid mTLS mode 'OFF'"},
+		{"mesh policy with invalid ejection percent", func(config *model.FileConfig) {
+			policy := model.MeshPolicy{OutlierDetection: &model.OutlierDetection{MaxEjectionPercent: 150}}
+			config.Mesh = &model.Mesh{Services: []model.MeshService{{Service: "backend", MeshPolicy: policy}}}
+		}, "mesh policy for service 'backend' has invalid maximum ejection percent 150"},
+		{"mesh policy for unknown service", func(config *model.FileConfig) {
+			config.Mesh = &model.Mesh{Services: []model.MeshService{{Service: "database"}}}
+		}, "mesh policy for service 'database' which does not exist"},
+		{"duplicate mesh policy", func(config *model.FileConfig) {
+			config.Mesh = &model.Mesh{Services: []model.MeshService{{Service: "backend"}, {Service: "backend"}}}
+		}, "duplicate mesh policy for service 'backend'"},
+
+		{"autoscaling", func(config *model.FileConfig) {
+			config.Services[1].Autoscaling = &model.Autoscaling{Kind: "hpa", MinReplicas: 1, MaxReplicas: 4, Cpu: 70}
+		}, ""},
+		{"autoscaling with replicas", func(config *model.FileConfig) {
+			config.Services[1].Clusters[0].Replicas = 2
+			config.Services[1].Clusters[0].Autoscaling = &model.Autoscaling{Kind: "hpa", MinReplicas: 1, MaxReplicas: 4, Cpu: 70}
+		}, "can't have both replicas and autoscaling on cluster 'cluster1'"},
+		{"invalid autoscaling kind", func(config *model.FileConfig) {
+			config.Services[1].Autoscaling = &model.Autoscaling{Kind: "vpa", MinReplicas: 1, MaxReplicas: 4}
+		}, "invalid autoscaling kind 'vpa'"},
+		{"maximum below minimum replicas", func(config *model.FileConfig) {
+			config.Services[1].Autoscaling = &model.Autoscaling{Kind: "hpa", MinReplicas: 4, MaxReplicas: 2, Cpu: 70}
+		}, "invalid maximum number of replicas on cluster 'cluster1'"},
+		{"KEDA metric without query", func(config *model.FileConfig) {
+			metric := &model.AutoscalingMetric{Name: "requests", Target: "100"}
+			config.Services[1].Autoscaling = &model.Autoscaling{Kind: "keda", MinReplicas: 1, MaxReplicas: 4, Metric: metric}
+		}, "needs a Prometheus query for the autoscaling metric"},
+
+		{"resource profile", func(config *model.FileConfig) {
+			config.Services[1].Profile = "small"
+		}, ""},
+		{"unknown resource profile", func(config *model.FileConfig) {
+			config.Services[1].Profile = "tiny"
+		}, "uses unknown resource profile 'tiny'"},
+		{"invalid resource profile in settings", func(config *model.FileConfig) {
+			profile := model.Resources{}
+			profile.Limits.Cpu = "fast"
+			config.Settings.Profiles = map[string]model.Resources{"tiny": profile}
+		}, "resource profile 'tiny' has invalid resource allocation 'fast'"},
+		{"request greater than limit", func(config *model.FileConfig) {
+			config.Services[1].Resources.Requests.Cpu = "2000m"
+			config.Services[1].Resources.Limits.Cpu = "1000m"
+		}, "CPU request '2000m' greater than its limit '1000m'"},
+		{"invalid QoS class", func(config *model.FileConfig) {
+			config.Services[1].QosClass = "Premium"
+		}, "invalid QoS class 'Premium'"},
+		{"QoS class Guaranteed", func(config *model.FileConfig) {
+			config.Services[1].QosClass = "Guaranteed"
+		}, ""},
+		{"QoS class Guaranteed with lower request", func(config *model.FileConfig) {
+			config.Services[1].QosClass = "Guaranteed"
+			config.Services[1].Resources.Requests.Memory = "128M"
+			config.Services[1].Resources.Limits.Memory = "256M"
+		}, "needs requests equal to its limits for QoS class Guaranteed"},
+		{"QoS class BestEffort with resources", func(config *model.FileConfig) {
+			config.Services[1].QosClass = "BestEffort"
+			config.Services[1].Resources.Limits.Cpu = "1000m"
+		}, "can't have resource allocations in QoS class BestEffort"},
+		{"QoS class Burstable with profile", func(config *model.FileConfig) {
+			config.Services[1].QosClass = "Burstable"
+			config.Services[1].Profile = "large"
+		}, ""},
+		{"vertical scaling", func(config *model.FileConfig) {
+			config.Services[1].VerticalScaling = &model.VerticalScaling{Mode: "Auto", MinCpu: "100m", MaxCpu: "2000m"}
+		}, ""},
+		{"invalid vertical scaling mode", func(config *model.FileConfig) {
+			config.Services[1].VerticalScaling = &model.VerticalScaling{Mode: "Always"}
+		}, "invalid vertical scaling mode 'Always'"},
+		{"vertical scaling minimum above maximum", func(config *model.FileConfig) {
+			config.Services[1].VerticalScaling = &model.VerticalScaling{Mode: "Auto", MinMemory: "2G", MaxMemory: "1G"}
+		}, "minimum memory '2G' greater than its maximum '1G'"},
+		{"vertical and horizontal scaling on CPU", func(config *model.FileConfig) {
+			config.Services[1].VerticalScaling = &model.VerticalScaling{Mode: "Auto"}
+			config.Services[1].Autoscaling = &model.Autoscaling{Kind: "hpa", MinReplicas: 1, MaxReplicas: 4, Cpu: 70}
+		}, "can't scale vertically and horizontally on CPU or memory"},
+
+		{"scheduling constraints", func(config *model.FileConfig) {
+			cluster := &config.Services[1].Clusters[0]
+			cluster.NodeSelector = map[string]string{"disktype": "ssd"}
+			cluster.NodeAffinity = []model.NodeAffinityRule{{Key: "zone", Operator: "In", Values: []string{"a"}, Weight: 50}}
+			cluster.ServiceAntiAffinity = []model.ServiceAffinityRule{{Service: "frontend"}}
+			cluster.Tolerations = []model.Toleration{{Key: "dedicated", Operator: "Equal", Value: "backend", Effect: "NoSchedule"}}
+			cluster.TopologySpread = []model.TopologySpread{{TopologyKey: "zone", MaxSkew: 1, WhenUnsatisfiable: "DoNotSchedule"}}
+		}, ""},
+		{"node and scheduling constraints", func(config *model.FileConfig) {
+			config.Services[1].Clusters[0].Node = "node1"
+			config.Services[1].Clusters[0].NodeSelector = map[string]string{"disktype": "ssd"}
+		}, "can't have both a node and scheduling constraints"},
+		{"node affinity without values", func(config *model.FileConfig) {
+			config.Services[1].Clusters[0].NodeAffinity = []model.NodeAffinityRule{{Key: "zone", Operator: "In"}}
+		}, "needs values for node affinity operator 'In'"},
+		{"affinity to service on another cluster", func(config *model.FileConfig) {
+			config.Services[0].Clusters[0].Cluster = "cluster2"
+			config.Services[1].Clusters[0].ServiceAffinity = []model.ServiceAffinityRule{{Service: "frontend"}}
+		}, "has affinity to service 'frontend', which is not deployed on the cluster"},
+		{"toleration value with operator Exists", func(config *model.FileConfig) {
+			config.Services[1].Clusters[0].Tolerations = []model.Toleration{{Key: "dedicated", Operator: "Exists", Value: "backend"}}
+		}, "can't have a toleration value with operator 'Exists'"},
+		{"toleration seconds without NoExecute", func(config *model.FileConfig) {
+			seconds := 60
+			config.Services[1].Clusters[0].Tolerations = []model.Toleration{{Key: "dedicated", Operator: "Exists", Effect: "NoSchedule", TolerationSeconds: &seconds}}
+		}, "can only set toleration seconds for effect 'NoExecute'"},
+		{"negative topology skew", func(config *model.FileConfig) {
+			config.Services[1].Clusters[0].TopologySpread = []model.TopologySpread{{TopologyKey: "zone", MaxSkew: -1, WhenUnsatisfiable: "DoNotSchedule"}}
+		}, "invalid maximum topology skew -1"},
+
+		{"MCS discovery", func(config *model.FileConfig) {
+			moveBackend("cluster2", "default")(config)
+			config.Settings.Discovery = &model.Discovery{Method: s.DiscoveryMcs}
+		}, ""},
+		{"invalid discovery method", func(config *model.FileConfig) {
+			config.Settings.Discovery = &model.Discovery{Method: "dns"}
+		}, "invalid discovery method 'dns'"},
+		{"invalid discovery address", func(config *model.FileConfig) {
+			config.Settings.Discovery = &model.Discovery{Method: s.DiscoveryExternal, Addresses: map[string]string{"backend": "Backend_1"}}
+		}, "discovery address of service 'backend' has invalid name 'Backend_1'"},
+		{"unreachable service", moveBackend("cluster2", "default"), "service 'frontend' on cluster 'cluster1' can't reach service 'backend'"},
+
+		{"entry point", func(config *model.FileConfig) {
+			config.Services[0].Endpoints[0].EntryPoint = true
+		}, ""},
+		{"invalid entry point kind", func(config *model.FileConfig) {
+			config.EntryPoints = &model.EntryPoints{Kind: "route"}
+		}, "invalid entry point kind 'route'"},
+		{"entry point class without ingress", func(config *model.FileConfig) {
+			config.EntryPoints = &model.EntryPoints{Kind: "gateway", Gateway: "public", Class: "nginx"}
+		}, "entry point class is only used by kind 'ingress'"},
+		{"entry point gateway without gateway kind", func(config *model.FileConfig) {
+			config.EntryPoints = &model.EntryPoints{Kind: "ingress", Gateway: "public"}
+		}, "entry point gateway is only used by kind 'gateway'"},
+		{"entry point host with node port", func(config *model.FileConfig) {
+			config.EntryPoints = &model.EntryPoints{Kind: "nodeport", Host: "example.com"}
+		}, "entry point host is only used by kinds 'ingress' and 'gateway'"},
+		{"wildcard entry point host", func(config *model.FileConfig) {
+			config.EntryPoints = &model.EntryPoints{Kind: "ingress", Host: "*.example.com"}
+		}, ""},
+		{"invalid entry point host", func(config *model.FileConfig) {
+			config.EntryPoints = &model.EntryPoints{Kind: "ingress", Host: "Example_com"}
+		}, "entry point host 'Example_com' is invalid"},
+		{"gRPC entry point with ingress", func(config *model.FileConfig) {
+			config.Services[1].Protocol = "grpc"
+			testEndpoint(config).EntryPoint = true
+		}, "gRPC service 'backend' has entry points"},
+		{"gRPC entry point with gateway", func(config *model.FileConfig) {
+			config.Services[1].Protocol = "grpc"
+			testEndpoint(config).EntryPoint = true
+			config.EntryPoints = &model.EntryPoints{Kind: "gateway", Gateway: "public"}
+		}, ""},
+		{"entry point paths of two services", func(config *model.FileConfig) {
+			config.Services[0].Endpoints[0].EntryPoint = true
+			testEndpoint(config).EntryPoint = true
+		}, "entry point path '/endpoint1' of service 'backend' on cluster 'cluster1' is already used by service 'frontend'"},
+		{"entry point paths on different clusters", func(config *model.FileConfig) {
+			config.Services[0].Endpoints[0].EntryPoint = true
+			testEndpoint(config).EntryPoint = true
+			config.Services[1].Clusters = []model.Cluster{{Cluster: "cluster2", Namespace: "default"}}
+			config.Settings.Discovery = &model.Discovery{Method: s.DiscoveryMcs}
+		}, ""},
+		{"entry point paths of HTTP and gRPC services", func(config *model.FileConfig) {
+			config.Services[0].Endpoints[0].EntryPoint = true
+			config.Services[1].Protocol = "grpc"
+			testEndpoint(config).EntryPoint = true
+			config.EntryPoints = &model.EntryPoints{Kind: "gateway", Gateway: "public"}
+		}, ""},
+
+		{"probes", func(config *model.FileConfig) {
+			config.Services[1].Probes = &model.Probes{Readiness: &model.Probe{SuccessThreshold: 2}, Liveness: &model.Probe{Period: 5}}
+			config.Services[1].Health = &model.Health{StartupDelay: 5, UnhealthyAfter: 1000}
+		}, ""},
+		{"negative probe values", func(config *model.FileConfig) {
+			config.Services[1].Probes = &model.Probes{Startup: &model.Probe{Period: -1}}
+		}, "startup probe of service 'backend' has negative values"},
+		{"liveness probe with success threshold", func(config *model.FileConfig) {
+			config.Services[1].Probes = &model.Probes{Liveness: &model.Probe{SuccessThreshold: 2}}
+		}, "liveness probe of service 'backend' can't have a success threshold"},
+		{"negative health values", func(config *model.FileConfig) {
+			config.Services[1].Health = &model.Health{MaxInFlight: -1}
+		}, "health of service 'backend' has negative values"},
+		{"unhealthy without liveness probe", func(config *model.FileConfig) {
+			config.Services[1].Health = &model.Health{UnhealthyAfter: 1000}
+		}, "becomes unhealthy after 1000 requests, but has no liveness probe"},
+
+		{"rollout", func(config *model.FileConfig) {
+			budget := &model.DisruptionBudget{MinAvailable: "50%"}
+			config.Services[1].Rollout = &model.Rollout{MaxUnavailable: "0", MaxSurge: "25%", DisruptionBudget: budget}
+		}, ""},
+		{"invalid max surge", func(config *model.FileConfig) {
+			config.Services[1].Rollout = &model.Rollout{MaxSurge: "one"}
+		}, "max surge of service 'backend' must be a number of pods or a percentage, got 'one'"},
+		{"max unavailable and max surge of 0", func(config *model.FileConfig) {
+			config.Services[1].Rollout = &model.Rollout{MaxUnavailable: "0%", MaxSurge: "0"}
+		}, "max unavailable and max surge of service 'backend' can't both be 0"},
+		{"max surge with storage", func(config *model.FileConfig) {
+			config.Services[1].Storage = &model.Storage{}
+			config.Services[1].Rollout = &model.Rollout{MaxSurge: "1"}
+		}, "StatefulSets can't have a max surge"},
+		{"disruption budget without values", func(config *model.FileConfig) {
+			config.Services[1].Rollout = &model.Rollout{DisruptionBudget: &model.DisruptionBudget{}}
+		}, "needs either min available or max unavailable"},
+		{"disruption budget with both values", func(config *model.FileConfig) {
+			config.Services[1].Rollout = &model.Rollout{DisruptionBudget: &model.DisruptionBudget{MinAvailable: "1", MaxUnavailable: "1"}}
+		}, "needs either min available or max unavailable"},
+		{"invalid disruption budget", func(config *model.FileConfig) {
+			config.Services[1].Rollout = &model.Rollout{DisruptionBudget: &model.DisruptionBudget{MaxUnavailable: "-1"}}
+		}, "disruption budget max unavailable of service 'backend' must be a number of pods or a percentage"},
+
+		{"versions", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "v1"}, {Name: "v2", Replicas: 1}}
+		}, ""},
+		{"weights with gamma", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "v1", Weight: 90}, {Name: "v2", Weight: 10}}
+			config.Settings.Gamma = true
+		}, ""},
+		{"weights with mesh", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "v1", Weight: 90}, {Name: "v2", Weight: 10}}
+			config.Mesh = &model.Mesh{}
+		}, ""},
+		{"weights without mesh or gamma", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "v1", Weight: 90}, {Name: "v2", Weight: 10}}
+		}, "weights of the versions of service 'backend' need a service mesh or the gamma setting"},
+		{"gamma with mesh", func(config *model.FileConfig) {
+			config.Settings.Gamma = true
+			config.Mesh = &model.Mesh{}
+		}, "gamma setting is only used without a service mesh"},
+		{"weights not adding up to 100", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "v1", Weight: 90}, {Name: "v2", Weight: 20}}
+			config.Settings.Gamma = true
+		}, "weights of the versions of service 'backend' must sum up to 100, got 110"},
+		{"negative weight", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "v1", Weight: 110}, {Name: "v2", Weight: -10}}
+			config.Settings.Gamma = true
+		}, "version 'v2' of service 'backend' has a negative weight"},
+		{"negative version replicas", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "v1", Replicas: -1}}
+		}, "version 'v1' of service 'backend' has a negative number of replicas"},
+		{"invalid version name", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "V1"}}
+		}, "version 'V1' of service 'backend' has invalid name"},
+		{"too long version name", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "v" + strings.Repeat("1", 60)}}
+		}, "results in invalid name 'backend-v1"},
+		{"duplicate version", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "v1"}, {Name: "v1"}}
+		}, "duplicate version 'v1' in service 'backend'"},
+		{"version with other endpoints", func(config *model.FileConfig) {
+			config.Services[1].Versions = []model.Version{{Name: "v1"}, {Name: "v2", Endpoints: []model.Endpoint{{Name: "endpoint2"}}}}
+		}, "version 'v2' of service 'backend' needs the same endpoints as the service"},
+		{"version calling other services", func(config *model.FileConfig) {
+			call := model.CalledService{Service: "frontend", Endpoint: "endpoint1"}
+			endpoint := model.Endpoint{Name: "endpoint1", NetworkComplexity: &model.NetworkComplexity{CalledServices: []model.CalledService{call}}}
+			config.Services[1].Versions = []model.Version{{Name: "v1"}, {Name: "v2", Endpoints: []model.Endpoint{endpoint}}}
+		}, "version 'v2' of service 'backend' calls service 'frontend', which the service doesn't call"},
+		{"invalid version endpoint", func(config *model.FileConfig) {
+			endpoint := model.Endpoint{Name: "endpoint1", CpuComplexity: &model.CpuComplexity{Mode: "cycles"}}
+			config.Services[1].Versions = []model.Version{{Name: "v1"}, {Name: "v2", Endpoints: []model.Endpoint{endpoint}}}
+		}, "invalid CPU mode 'cycles'"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := loadTestDescription(t, test.modify)
+			if test.err == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Fatalf("expected error containing '%s', got: %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestApplyResourceDefaults(t *testing.T) {
+	resources := func(requestCpu, requestMemory, limitCpu, limitMemory string) model.Resources {
+		var resources model.Resources
+		resources.Requests.Cpu = requestCpu
+		resources.Requests.Memory = requestMemory
+		resources.Limits.Cpu = limitCpu
+		resources.Limits.Memory = limitMemory
+		return resources
+	}
+
+	tests := []struct {
+		name      string
+		service   model.Service
+		resources model.Resources
+	}{
+		{"defaults", model.Service{}, resources("500m", "256M", "1000m", "1024M")},
+		{"profile", model.Service{Profile: "small"}, resources("100m", "64M", "250m", "256M")},
+		{"profile and allocation", model.Service{Profile: "small", Resources: resources("", "", "500m", "")}, resources("100m", "64M", "500m", "256M")},
+		{"limit below default request", model.Service{Resources: resources("", "", "200m", "128M")}, resources("200m", "128M", "200m", "128M")},
+		{"request above default limit", model.Service{Resources: resources("2000m", "2G", "", "")}, resources("2000m", "2G", "2000m", "2G")},
+		{"Guaranteed with request", model.Service{QosClass: "Guaranteed", Resources: resources("200m", "", "", "")}, resources("200m", "1024M", "200m", "1024M")},
+		{"BestEffort", model.Service{QosClass: "BestEffort"}, model.Resources{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := s.CreateFileConfig()
+			applyResourceDefaults(&config, &test.service)
+			if test.service.Resources != test.resources {
+				t.Fatalf("expected resources %+v, got %+v", test.resources, test.service.Resources)
+			}
+		})
+	}
+}
+
+func TestValidateProbes(t *testing.T) {
+	tests := []struct {
+		name    string
+		service model.Service
+		err     string
+	}{
+		{"no probes", model.Service{Name: "backend"}, ""},
+		{"no probes with health", model.Service{Name: "backend", Health: &model.Health{UnreadyAfter: 10}}, ""},
+		{"no probes and unhealthy", model.Service{Name: "backend", Health: &model.Health{UnhealthyAfter: 10}},
+			"service 'backend' becomes unhealthy after 10 requests, but has no liveness probe"},
+		{"liveness probe and unhealthy", model.Service{Name: "backend", Probes: &model.Probes{Liveness: &model.Probe{}}, Health: &model.Health{UnhealthyAfter: 10}}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateProbes(&test.service)
+			if test.err == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if test.err != "" && (err == nil || err.Error() != test.err) {
+				t.Fatalf("expected error '%s', got: %v", test.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadgen
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Returns the time of the arrival after the arrival at t, relative to the start of the test
+type arrivals func(t time.Duration) time.Duration
+
+// Converts a duration in seconds, as used in input files and flags, to a time.Duration
+func Seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// Returns the arrivals of an open-loop pattern
+func openArrivals(options *Options) (arrivals, error) {
+	if options.Pattern != "trace" && options.Rate <= 0 {
+		return nil, fmt.Errorf("pattern '%s' needs a positive rate", options.Pattern)
+	}
+
+	switch options.Pattern {
+	case "constant":
+		return func(t time.Duration) time.Duration {
+			return t + Seconds(1/options.Rate)
+		}, nil
+	case "poisson":
+		// Exponentially distributed inter-arrival times
+		return func(t time.Duration) time.Duration {
+			return t + Seconds(rand.ExpFloat64()/options.Rate)
+		}, nil
+	case "step":
+		if options.StepDuration <= 0 {
+			return nil, fmt.Errorf("pattern 'step' needs a positive step duration")
+		}
+		return func(t time.Duration) time.Duration {
+			steps := math.Floor(float64(t) / float64(options.StepDuration))
+			rate := options.Rate + options.StepRate*steps
+			if rate <= 0 {
+				// Wait for the next step
+				return time.Duration(steps+1) * options.StepDuration
+			}
+			return t + Seconds(1/rate)
+		}, nil
+	case "ramp":
+		if options.EndRate <= 0 {
+			return nil, fmt.Errorf("pattern 'ramp' needs a positive end rate")
+		}
+		return func(t time.Duration) time.Duration {
+			rate := options.Rate + (options.EndRate-options.Rate)*float64(t)/float64(options.Duration)
+			return t + Seconds(1/rate)
+		}, nil
+	case "trace":
+		if len(options.Trace) == 0 {
+			return nil, fmt.Errorf("pattern 'trace' needs a trace with at least one arrival")
+		}
+		next := 0
+		return func(t time.Duration) time.Duration {
+			if next >= len(options.Trace) {
+				// No more arrivals
+				return options.Duration
+			}
+			next++
+			return options.Trace[next-1]
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown pattern '%s'", options.Pattern)
+	}
+}
+
+// Reads a trace with the arrival time of one request in seconds after the start of the test on every line
+// Empty lines and lines starting with '#' are ignored
+func ReadTrace(filename string) ([]time.Duration, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	trace := []time.Duration{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		arrival, err := strconv.ParseFloat(text, 64)
+		if err != nil || arrival < 0 {
+			return nil, fmt.Errorf("invalid arrival time '%s' on line %d of %s", text, line, filename)
+		}
+		trace = append(trace, Seconds(arrival))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(trace, func(i, j int) bool { return trace[i] < trace[j] })
+	return trace, nil
+}
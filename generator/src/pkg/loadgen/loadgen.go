@@ -0,0 +1,269 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadgen
+
+import (
+	s "application-generator/src/pkg/service"
+	model "application-model"
+	"application-model/generated"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Parameters of a load test
+type Options struct {
+	Targets []*Target
+	// "closed" for a fixed number of users, or an open-loop arrival pattern: "constant", "poisson", "step", "ramp" or "trace"
+	Pattern  string
+	Duration time.Duration
+
+	// Closed-loop users and the time they wait between receiving a response and sending the next request
+	Users     int
+	ThinkTime time.Duration
+
+	// Open-loop arrivals per second
+	Rate         float64
+	EndRate      float64
+	StepRate     float64
+	StepDuration time.Duration
+	Trace        []time.Duration
+	// Open-loop arrivals are dropped when this many requests are in flight
+	MaxInFlight int
+
+	Timeout     time.Duration
+	PayloadSize int
+	// HTTP headers or gRPC metadata sent with every request
+	Headers map[string]string
+	// Length of the intervals that statistics are reported for
+	Interval time.Duration
+}
+
+// Returns the options of the load test described by the workload section of config
+func WorkloadOptions(config *model.FileConfig, addresses map[string]string) (*Options, error) {
+	workload := config.Workload
+	if workload == nil {
+		return nil, fmt.Errorf("the input file has no workload section")
+	}
+
+	targets, err := Targets(config, workload.Endpoints, workload.Cluster, addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	trace := []time.Duration{}
+	for _, arrival := range workload.Trace {
+		trace = append(trace, Seconds(arrival))
+	}
+	sort.Slice(trace, func(i, j int) bool { return trace[i] < trace[j] })
+
+	return &Options{
+		Targets:      targets,
+		Pattern:      workload.Pattern,
+		Duration:     Seconds(workload.Duration),
+		Users:        workload.Users,
+		ThinkTime:    Seconds(workload.ThinkTime),
+		Rate:         workload.Rate,
+		EndRate:      workload.EndRate,
+		StepRate:     workload.StepRate,
+		StepDuration: Seconds(workload.StepDuration),
+		Trace:        trace,
+		MaxInFlight:  workload.MaxInFlight,
+		Timeout:      Seconds(workload.Timeout),
+		PayloadSize:  workload.PayloadSize,
+		Headers:      workload.Headers,
+		Interval:     Seconds(workload.Interval),
+	}, nil
+}
+
+type runner struct {
+	options  *Options
+	client   *http.Client
+	recorder *Recorder
+	// Running sum of the target weights
+	weights []float64
+}
+
+// Returns a random target, chosen in proportion to the target weights
+func (r *runner) target() *Target {
+	x := rand.Float64() * r.weights[len(r.weights)-1]
+	i := sort.SearchFloat64s(r.weights, x)
+	if i == len(r.weights) {
+		i--
+	}
+	return r.options.Targets[i]
+}
+
+// Sends one request to a random target and records the result
+func (r *runner) request(ctx context.Context) {
+	target := r.target()
+	request := &generated.Request{Payload: randomPayload(r.options.PayloadSize)}
+
+	requestCtx, cancel := context.WithTimeout(ctx, r.options.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	status, response, err := target.send(requestCtx, r.client, request, r.options.Headers)
+	end := time.Now()
+
+	// Requests cancelled or completed after the test ended are not counted
+	if deadline, _ := ctx.Deadline(); ctx.Err() != nil || end.After(deadline) {
+		return
+	}
+	r.recorder.Record(end, end.Sub(start), status, err != nil, response)
+}
+
+// Every user sends a request, waits for the response and the think time and repeats until ctx is done
+func (r *runner) closedLoop(ctx context.Context) error {
+	if r.options.Users < 1 {
+		return fmt.Errorf("pattern 'closed' needs at least one user")
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < r.options.Users; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				r.request(ctx)
+
+				select {
+				case <-time.After(r.options.ThinkTime):
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	return nil
+}
+
+// Requests are sent at the arrival times of the pattern, independent of how many responses were received
+func (r *runner) openLoop(ctx context.Context, start time.Time) error {
+	next, err := openArrivals(r.options)
+	if err != nil {
+		return err
+	}
+
+	wg := sync.WaitGroup{}
+	inFlight := make(chan struct{}, r.options.MaxInFlight)
+
+	for t := next(0); t < r.options.Duration; t = next(t) {
+		select {
+		case <-time.After(time.Until(start.Add(t))):
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		select {
+		case inFlight <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-inFlight }()
+				r.request(ctx)
+			}()
+		default:
+			r.recorder.Drop()
+		}
+	}
+
+	wg.Wait()
+	return nil
+}
+
+// Runs a load test against the targets and returns the statistics
+// The statistics of every interval are written to progress while the test is running
+func Run(options *Options, progress io.Writer) (*Report, error) {
+	if len(options.Targets) == 0 {
+		return nil, fmt.Errorf("at least one target endpoint is required")
+	}
+	if options.Interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive")
+	}
+	if options.MaxInFlight < 1 {
+		return nil, fmt.Errorf("at least one request must be allowed in flight")
+	}
+
+	weights := []float64{}
+	sum := 0.0
+	for _, target := range options.Targets {
+		// Targets created without a weight are chosen as often as targets with the default weight
+		weight := target.Weight
+		if weight <= 0 {
+			weight = s.WlWeightDefault
+		}
+		sum += weight
+		weights = append(weights, sum)
+
+		if err := target.connect(); err != nil {
+			return nil, err
+		}
+		defer target.close()
+	}
+
+	start := time.Now()
+	r := &runner{
+		options: options,
+		client: &http.Client{
+			Transport: &http.Transport{MaxIdleConnsPerHost: options.MaxInFlight},
+		},
+		recorder: NewRecorder(start, options.Interval),
+		weights:  weights,
+	}
+
+	// Requests still in flight when the test ends are cancelled
+	ctx, cancel := context.WithDeadline(context.Background(), start.Add(options.Duration))
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(options.Interval)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			select {
+			case <-ticker.C:
+				interval := r.recorder.Interval(i)
+				fmt.Fprintf(progress, "%.0fs: requests=%d throughput=%.2f/s errors=%d p50=%.3fms p99=%.3fms\n",
+					interval.Time, interval.Requests, interval.Throughput, interval.Errors, interval.LatencyP50*1000, interval.LatencyP99*1000)
+			case <-done:
+				return
+			}
+		}
+	}()
+	defer close(done)
+
+	var err error
+	if options.Pattern == "closed" {
+		err = r.closedLoop(ctx)
+	} else {
+		err = r.openLoop(ctx, start)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.recorder.Report(), nil
+}
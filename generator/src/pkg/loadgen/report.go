@@ -0,0 +1,320 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadgen
+
+import (
+	"application-model/generated"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Latency, throughput and errors of the requests completed in an interval
+type IntervalStats struct {
+	// Seconds after the start of the test at the end of the interval
+	Time        float64 `json:"time"`
+	Requests    int     `json:"requests"`
+	Throughput  float64 `json:"throughput"`
+	Errors      int     `json:"errors"`
+	ErrorRate   float64 `json:"error_rate"`
+	LatencyMean float64 `json:"latency_mean"`
+	LatencyP50  float64 `json:"latency_p50"`
+	LatencyP90  float64 `json:"latency_p90"`
+	LatencyP99  float64 `json:"latency_p99"`
+	LatencyMax  float64 `json:"latency_max"`
+}
+
+// Task responses of all requests combined for a service endpoint
+type TaskSummary struct {
+	CpuTime       float64 `json:"cpu_time"`
+	LockWaitTime  float64 `json:"lock_wait_time"`
+	PayloadBytes  int64   `json:"payload_bytes"`
+	StorageHits   int64   `json:"storage_hits"`
+	StorageMisses int64   `json:"storage_misses"`
+	StorageWrites int64   `json:"storage_writes"`
+	NetworkTasks  int     `json:"network_tasks"`
+	Aborted       int     `json:"aborted"`
+}
+
+type Report struct {
+	Intervals []IntervalStats `json:"intervals"`
+	Summary   IntervalStats   `json:"summary"`
+	Statuses  map[string]int  `json:"statuses"`
+	// Open-loop arrivals that were not sent because too many requests were in flight
+	Dropped int                     `json:"dropped"`
+	Tasks   map[string]*TaskSummary `json:"tasks"`
+}
+
+type window struct {
+	latencies []float64
+	errors    int
+}
+
+// Collects the results of all requests of a test
+type Recorder struct {
+	mutex     sync.Mutex
+	start     time.Time
+	interval  time.Duration
+	windows   []*window
+	statuses  map[string]int
+	dropped   int
+	tasks     map[string]*TaskSummary
+	completed time.Duration
+}
+
+func NewRecorder(start time.Time, interval time.Duration) *Recorder {
+	return &Recorder{
+		start:    start,
+		interval: interval,
+		statuses: make(map[string]int),
+		tasks:    make(map[string]*TaskSummary),
+	}
+}
+
+// Records a request that completed at end
+func (r *Recorder) Record(end time.Time, latency time.Duration, status string, failed bool, response *generated.Response) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	elapsed := end.Sub(r.start)
+	index := int(elapsed / r.interval)
+	for len(r.windows) <= index {
+		r.windows = append(r.windows, &window{})
+	}
+	if elapsed > r.completed {
+		r.completed = elapsed
+	}
+
+	w := r.windows[index]
+	w.latencies = append(w.latencies, latency.Seconds())
+	if failed {
+		w.errors++
+	}
+	r.statuses[status]++
+
+	if response != nil && response.Tasks != nil {
+		r.recordTasks(response.Tasks)
+	}
+}
+
+// Records an arrival that was not sent
+func (r *Recorder) Drop() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.dropped++
+}
+
+// Keys of services that executed a task more than once in a request end with a counter
+var uniqueKeySuffix = regexp.MustCompile(`\.\d+$`)
+
+func (r *Recorder) task(key string) *TaskSummary {
+	key = uniqueKeySuffix.ReplaceAllString(key, "")
+	summary, ok := r.tasks[key]
+	if !ok {
+		summary = &TaskSummary{}
+		r.tasks[key] = summary
+	}
+	return summary
+}
+
+func (r *Recorder) recordTasks(tasks *generated.TaskResponses) {
+	if tasks.CpuTask != nil {
+		for k, v := range tasks.CpuTask.Services {
+			r.task(k).CpuTime += float64(v)
+		}
+		for _, k := range tasks.CpuTask.Aborted {
+			r.task(k).Aborted++
+		}
+	}
+	if tasks.LockTask != nil {
+		for k, v := range tasks.LockTask.Services {
+			r.task(k).LockWaitTime += float64(v)
+		}
+		for _, k := range tasks.LockTask.Aborted {
+			r.task(k).Aborted++
+		}
+	}
+	if tasks.PayloadTask != nil {
+		for k, v := range tasks.PayloadTask.Services {
+			r.task(k).PayloadBytes += int64(v)
+		}
+		for _, k := range tasks.PayloadTask.Aborted {
+			r.task(k).Aborted++
+		}
+	}
+	if tasks.StorageTask != nil {
+		for k, v := range tasks.StorageTask.Services {
+			summary := r.task(k)
+			summary.StorageHits += int64(v.Hits)
+			summary.StorageMisses += int64(v.Misses)
+			summary.StorageWrites += int64(v.Writes)
+		}
+		for _, k := range tasks.StorageTask.Aborted {
+			r.task(k).Aborted++
+		}
+	}
+	if tasks.NetworkTask != nil {
+		for _, k := range tasks.NetworkTask.Services {
+			r.task(k).NetworkTasks++
+		}
+		for _, k := range tasks.NetworkTask.Aborted {
+			r.task(k).Aborted++
+		}
+	}
+}
+
+// Returns the value at quantile q (0-1) of sorted values
+func percentile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(q*float64(len(sorted))+0.5) - 1
+	if index < 0 {
+		index = 0
+	} else if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
+
+func stats(latencies []float64, errors int, end float64, duration float64) IntervalStats {
+	sorted := append([]float64{}, latencies...)
+	sort.Float64s(sorted)
+
+	stats := IntervalStats{
+		Time:     end,
+		Requests: len(sorted),
+		Errors:   errors,
+	}
+	if duration > 0 {
+		stats.Throughput = float64(len(sorted)) / duration
+	}
+	if len(sorted) > 0 {
+		sum := 0.0
+		for _, latency := range sorted {
+			sum += latency
+		}
+		stats.ErrorRate = float64(errors) / float64(len(sorted))
+		stats.LatencyMean = sum / float64(len(sorted))
+		stats.LatencyP50 = percentile(sorted, 0.50)
+		stats.LatencyP90 = percentile(sorted, 0.90)
+		stats.LatencyP99 = percentile(sorted, 0.99)
+		stats.LatencyMax = sorted[len(sorted)-1]
+	}
+	return stats
+}
+
+// Returns the statistics of the completed interval with the given index
+func (r *Recorder) Interval(index int) IntervalStats {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	end := float64(index+1) * r.interval.Seconds()
+	if index >= len(r.windows) {
+		return stats(nil, 0, end, r.interval.Seconds())
+	}
+	return stats(r.windows[index].latencies, r.windows[index].errors, end, r.interval.Seconds())
+}
+
+// Returns the statistics of all intervals and the whole test
+func (r *Recorder) Report() *Report {
+	report := &Report{}
+	for i := range r.windows {
+		report.Intervals = append(report.Intervals, r.Interval(i))
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	latencies, errors := []float64{}, 0
+	for _, w := range r.windows {
+		latencies = append(latencies, w.latencies...)
+		errors += w.errors
+	}
+	report.Summary = stats(latencies, errors, r.completed.Seconds(), r.completed.Seconds())
+	report.Statuses = r.statuses
+	report.Dropped = r.dropped
+	report.Tasks = r.tasks
+
+	return report
+}
+
+// Writes the report as JSON
+func (report *Report) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// Writes the statistics of every interval as CSV
+func (report *Report) WriteCSV(writer io.Writer) error {
+	csvWriter := csv.NewWriter(writer)
+	csvWriter.Write([]string{
+		"time", "requests", "throughput", "errors", "error_rate",
+		"latency_mean", "latency_p50", "latency_p90", "latency_p99", "latency_max",
+	})
+
+	format := func(f float64) string {
+		return strconv.FormatFloat(f, 'g', 6, 64)
+	}
+	for _, interval := range report.Intervals {
+		csvWriter.Write([]string{
+			format(interval.Time), strconv.Itoa(interval.Requests), format(interval.Throughput),
+			strconv.Itoa(interval.Errors), format(interval.ErrorRate), format(interval.LatencyMean),
+			format(interval.LatencyP50), format(interval.LatencyP90), format(interval.LatencyP99), format(interval.LatencyMax),
+		})
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// Writes a summary of the test and the combined task responses in a human-readable format
+func (report *Report) WriteSummary(writer io.Writer) {
+	summary := report.Summary
+	fmt.Fprintf(writer, "Requests: %d, throughput: %.2f/s, errors: %d (%.2f%%), dropped: %d\n",
+		summary.Requests, summary.Throughput, summary.Errors, summary.ErrorRate*100, report.Dropped)
+	fmt.Fprintf(writer, "Latency: mean=%.3fms p50=%.3fms p90=%.3fms p99=%.3fms max=%.3fms\n",
+		summary.LatencyMean*1000, summary.LatencyP50*1000, summary.LatencyP90*1000, summary.LatencyP99*1000, summary.LatencyMax*1000)
+
+	statuses := make([]string, 0, len(report.Statuses))
+	for status := range report.Statuses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		fmt.Fprintf(writer, "Status %s: %d\n", status, report.Statuses[status])
+	}
+
+	keys := make([]string, 0, len(report.Tasks))
+	for key := range report.Tasks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		task := report.Tasks[key]
+		fmt.Fprintf(writer, "%s: cpuTime=%.3fs lockWaitTime=%.3fs payloadBytes=%d storageHits=%d storageMisses=%d storageWrites=%d networkTasks=%d aborted=%d\n",
+			key, task.CpuTime, task.LockWaitTime, task.PayloadBytes, task.StorageHits, task.StorageMisses, task.StorageWrites, task.NetworkTasks, task.Aborted)
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadgen
+
+import (
+	s "application-generator/src/pkg/service"
+	model "application-model"
+	"application-model/generated"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iancoleman/strcase"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const characters = "abcdefghijklmnopqrstuvwxyz"
+
+// An entry endpoint that requests are sent to
+type Target struct {
+	Service  string
+	Endpoint string
+	Protocol string
+	Address  string
+	// Share of the requests sent to the target relative to the other targets
+	Weight float64
+
+	conn *grpc.ClientConn
+}
+
+// Parses target endpoints in the format service/endpoint, optionally followed by :weight
+func ParseEndpoints(names []string) ([]model.WorkloadEndpoint, error) {
+	endpoints := []model.WorkloadEndpoint{}
+	for _, name := range names {
+		endpoint := model.WorkloadEndpoint{Weight: s.WlWeightDefault}
+
+		name, weight, found := strings.Cut(name, ":")
+		if found {
+			var err error
+			if endpoint.Weight, err = strconv.ParseFloat(weight, 64); err != nil {
+				return nil, fmt.Errorf("target '%s' has invalid weight '%s'", name, weight)
+			}
+		}
+		if endpoint.Service, endpoint.Endpoint, found = strings.Cut(name, "/"); !found {
+			return nil, fmt.Errorf("target '%s' is not in the format service/endpoint", name)
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, nil
+}
+
+// Returns the targets for the endpoints
+// Requests are sent to the Kubernetes service unless addresses contains another host:port for the service
+// The service name is qualified with its namespace in cluster if cluster is not empty
+func Targets(config *model.FileConfig, endpoints []model.WorkloadEndpoint, cluster string, addresses map[string]string) ([]*Target, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("at least one target endpoint is required")
+	}
+
+	targets := []*Target{}
+	for _, workloadEndpoint := range endpoints {
+		if workloadEndpoint.Weight <= 0 {
+			return nil, fmt.Errorf("target '%s/%s' must have a positive weight", workloadEndpoint.Service, workloadEndpoint.Endpoint)
+		}
+
+		var target *Target
+		for _, service := range config.Services {
+			for _, endpoint := range service.Endpoints {
+				if service.Name == workloadEndpoint.Service && endpoint.Name == workloadEndpoint.Endpoint {
+					target = &Target{
+						Service:  service.Name,
+						Endpoint: endpoint.Name,
+						Protocol: service.Protocol,
+						Address:  fmt.Sprintf("%s:%d", service.Name, s.DefaultExtPort),
+						Weight:   workloadEndpoint.Weight,
+					}
+
+					for _, serviceCluster := range service.Clusters {
+						if serviceCluster.Cluster == cluster {
+							target.Address = fmt.Sprintf("%s.%s:%d", service.Name, serviceCluster.Namespace, s.DefaultExtPort)
+						}
+					}
+				}
+			}
+		}
+		if target == nil {
+			return nil, fmt.Errorf("target '%s/%s' is not an endpoint in the input file", workloadEndpoint.Service, workloadEndpoint.Endpoint)
+		}
+		if address, ok := addresses[target.Service]; ok {
+			target.Address = address
+		}
+
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
+
+// Generates a random payload of n characters
+func randomPayload(n int) string {
+	payload := make([]byte, n)
+	for i := range payload {
+		payload[i] = characters[rand.Intn(len(characters))]
+	}
+	return string(payload)
+}
+
+// Opens the gRPC connection of the target, which is shared by all requests
+func (t *Target) connect() error {
+	if t.Protocol != "grpc" {
+		return nil
+	}
+
+	conn, err := grpc.Dial(t.Address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	t.conn = conn
+	return err
+}
+
+func (t *Target) close() {
+	if t.conn != nil {
+		t.conn.Close()
+	}
+}
+
+// Sends a request to the target and returns the status and the response, if one was received
+// The request failed if err is not nil
+func (t *Target) send(ctx context.Context, client *http.Client, request *generated.Request, headers map[string]string) (string, *generated.Response, error) {
+	if t.Protocol == "grpc" {
+		return t.sendGRPC(ctx, request, headers)
+	}
+	return t.sendHTTP(ctx, client, request, headers)
+}
+
+func (t *Target) sendHTTP(ctx context.Context, client *http.Client, request *generated.Request, headers map[string]string) (string, *generated.Response, error) {
+	data, err := protojson.Marshal(request)
+	if err != nil {
+		return "", nil, err
+	}
+
+	url := fmt.Sprintf("http://%s/%s", t.Address, t.Endpoint)
+	httpRequest, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(data))
+	if err != nil {
+		return "", nil, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		httpRequest.Header.Set(key, value)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		httpRequest.Header.Set(model.TimeoutHeader, strconv.FormatInt(time.Until(deadline).Milliseconds(), 10))
+	}
+
+	httpResponse, err := client.Do(httpRequest)
+	if err != nil {
+		return "error", nil, err
+	}
+	defer httpResponse.Body.Close()
+
+	body, err := io.ReadAll(httpResponse.Body)
+	status := fmt.Sprintf("%d %s", httpResponse.StatusCode, http.StatusText(httpResponse.StatusCode))
+	if err != nil {
+		return status, nil, err
+	}
+
+	// Error responses of the emulator also contain the tasks that were executed
+	response := &generated.Response{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, response); err != nil {
+		response = nil
+	}
+	if httpResponse.StatusCode != http.StatusOK {
+		return status, response, fmt.Errorf("unexpected status %s", status)
+	}
+	return status, response, nil
+}
+
+func (t *Target) sendGRPC(ctx context.Context, request *generated.Request, headers map[string]string) (string, *generated.Response, error) {
+	for key, value := range headers {
+		ctx = metadata.AppendToOutgoingContext(ctx, key, value)
+	}
+
+	// Generated gRPC services are named after the service and methods after the endpoint
+	method := fmt.Sprintf("/generated.%s/%s", strcase.ToCamel(t.Service), strcase.ToCamel(t.Endpoint))
+	response := &generated.Response{}
+	err := t.conn.Invoke(ctx, method, request, response)
+	if err != nil {
+		return status.Code(err).String(), nil, err
+	}
+	return status.Code(nil).String(), response, nil
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	s "application-generator/src/pkg/service"
+	model "application-model"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Parameters of a local run
+type Options struct {
+	// Path of the application emulator binary
+	Emulator string
+	// Port of the first service, the following services listen on the next ports
+	BasePort int
+	// Directory for the config files and persistent storage of the services
+	Dir string
+	// Time to wait for all services to accept connections
+	StartTimeout time.Duration
+}
+
+// A service running as a child process of the generator
+type Process struct {
+	Service string
+	Port    int
+
+	cmd *exec.Cmd
+	// Closed when the process exited
+	done chan struct{}
+}
+
+// Writes lines of output from several processes without interleaving them
+type prefixWriter struct {
+	mutex  sync.Mutex
+	output io.Writer
+	width  int
+}
+
+func (w *prefixWriter) copy(service string, reader io.Reader) {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		w.mutex.Lock()
+		fmt.Fprintf(w.output, "%-*s | %s\n", w.width, service, scanner.Text())
+		w.mutex.Unlock()
+	}
+}
+
+// Returns the port of every service, in the order of the input file
+func Ports(config *model.FileConfig, basePort int) map[string]int {
+	ports := map[string]int{}
+	for i, service := range config.Services {
+		ports[service.Name] = basePort + i
+	}
+	return ports
+}
+
+// Returns the addresses of the services as host:port on localhost
+func Addresses(ports map[string]int) map[string]string {
+	addresses := map[string]string{}
+	for service, port := range ports {
+		addresses[service] = fmt.Sprintf("localhost:%d", port)
+	}
+	return addresses
+}
+
+// Returns the config map of every service
+// Called services are reached on localhost instead of through their Kubernetes service
+func ConfigMaps(config *model.FileConfig, ports map[string]int, dir string) map[string]*model.ConfigMap {
+	configMaps := map[string]*model.ConfigMap{}
+	for _, service := range config.Services {
+		storagePath := ""
+		if service.Storage != nil {
+			storagePath = filepath.Join(dir, service.Name+"-data")
+		}
+
+		configMap := s.CreateConfigMap(service.Processes, config.Settings.Logging, config.Settings.DeadlineReduction,
+			service.Resources.Limits.Cpu, storagePath, service.DisableOverrides, service.Protocol, service.Endpoints, service.Health)
+		configMap.Port = ports[service.Name]
+		configMap.Addresses = Addresses(ports)
+
+		configMaps[service.Name] = configMap
+	}
+	return configMaps
+}
+
+// Starts every service in the input file as a child process running the application emulator
+// The output of all processes is written to output, prefixed with the service name
+func Start(config *model.FileConfig, options *Options, output io.Writer) ([]*Process, error) {
+	emulator, err := exec.LookPath(options.Emulator)
+	if err != nil {
+		return nil, err
+	}
+
+	ports := Ports(config, options.BasePort)
+	configMaps := ConfigMaps(config, ports, options.Dir)
+
+	writer := &prefixWriter{output: output}
+	for _, service := range config.Services {
+		if len(service.Name) > writer.width {
+			writer.width = len(service.Name)
+		}
+	}
+
+	processes := []*Process{}
+	for _, service := range config.Services {
+		if storagePath := configMaps[service.Name].StoragePath; storagePath != "" {
+			if err := os.MkdirAll(storagePath, 0777); err != nil {
+				Stop(processes)
+				return nil, err
+			}
+		}
+
+		data, err := json.Marshal(configMaps[service.Name])
+		if err != nil {
+			Stop(processes)
+			return nil, err
+		}
+		configPath := filepath.Join(options.Dir, service.Name+".json")
+		if err := os.WriteFile(configPath, data, 0644); err != nil {
+			Stop(processes)
+			return nil, err
+		}
+
+		cmd := exec.Command(emulator)
+		cmd.Env = append(os.Environ(), "SERVICE_NAME="+service.Name, "CONF="+configPath)
+		reader, writerPipe := io.Pipe()
+		cmd.Stdout = writerPipe
+		cmd.Stderr = writerPipe
+		if err := cmd.Start(); err != nil {
+			writerPipe.Close()
+			Stop(processes)
+			return nil, fmt.Errorf("could not start service '%s': %s", service.Name, err)
+		}
+
+		process := &Process{Service: service.Name, Port: ports[service.Name], cmd: cmd, done: make(chan struct{})}
+		go writer.copy(process.Service, reader)
+		go func() {
+			cmd.Wait()
+			writerPipe.Close()
+			close(process.done)
+		}()
+
+		processes = append(processes, process)
+	}
+
+	return processes, nil
+}
+
+// Waits until every process accepts connections on its port
+func WaitReady(ctx context.Context, processes []*Process, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for _, process := range processes {
+		address := fmt.Sprintf("localhost:%d", process.Port)
+		for {
+			conn, err := net.DialTimeout("tcp", address, time.Second)
+			if err == nil {
+				conn.Close()
+				break
+			}
+			if process.Exited() {
+				return fmt.Errorf("service '%s' exited before accepting connections", process.Service)
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("service '%s' did not accept connections on %s within %s", process.Service, address, timeout)
+			}
+
+			select {
+			case <-time.After(100 * time.Millisecond):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns true if the process is no longer running
+func (p *Process) Exited() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Kills all processes and waits until they exited
+func Stop(processes []*Process) {
+	for _, process := range processes {
+		process.cmd.Process.Kill()
+	}
+	for _, process := range processes {
+		<-process.done
+	}
+}
+
+// Starts every service, waits until ctx is done or one of the services exits and stops all services
+// The addresses of the services are written to output once all of them accept connections
+func Run(ctx context.Context, config *model.FileConfig, options *Options, output io.Writer) error {
+	processes, err := Start(config, options, output)
+	if err != nil {
+		return err
+	}
+	defer Stop(processes)
+
+	if err := WaitReady(ctx, processes, options.StartTimeout); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+
+	addresses := []string{}
+	for _, process := range processes {
+		addresses = append(addresses, fmt.Sprintf("%s=localhost:%d", process.Service, process.Port))
+	}
+	sort.Strings(addresses)
+	fmt.Fprintf(output, "All services are ready, load can be generated with --address %s\n", strings.Join(addresses, ","))
+
+	exited := make(chan *Process, len(processes))
+	for _, process := range processes {
+		process := process
+		go func() {
+			<-process.done
+			exited <- process
+		}()
+	}
+
+	select {
+	case process := <-exited:
+		return fmt.Errorf("service '%s' exited: %s", process.Service, process.cmd.ProcessState)
+	case <-ctx.Done():
+		return nil
+	}
+}
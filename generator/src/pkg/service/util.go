@@ -32,6 +32,22 @@ const (
 	VolumeName = "config-data-volume"
 	VolumePath = "/usr/src/emulator/config"
 
+	StorageVolumeName = "storage-data-volume"
+	StorageVolumePath = "/usr/src/emulator/data"
+	// The headless Service that governs the StatefulSet of a service is named after the service with this suffix
+	HeadlessServiceSuffix = "-headless"
+
+	KustomizeDirectory  = "kustomize"
+	KustomizeAPIVersion = "kustomize.config.k8s.io/v1beta1"
+
+	HelmDirectory    = "helm"
+	HelmChartName    = "hydragen-application"
+	HelmChartVersion = "0.1.0"
+
+	ComposeDirectory       = "compose"
+	ComposeNetworkName     = "hydragen"
+	ComposeHostPortDefault = 5000
+
 	SourceImageURLProd = "ghcr.io/ericssonresearch/cloud-native-app-simulator"
 	SourceImageName    = "hydragen-base"
 	// TODO: Update the version here once everything is released
@@ -52,25 +68,103 @@ const (
 
 	ClusterNamespaceDefault = "default"
 
+	ClusterTopologyKeyDefault        = "kubernetes.io/hostname"
+	ClusterMaxSkewDefault            = 1
+	ClusterWhenUnsatisfiableDefault  = "DoNotSchedule"
+	ClusterTolerationOperatorDefault = "Equal"
+
 	RequestsCPUDefault    = "500m"
 	RequestsMemoryDefault = "256M"
 	LimitsCPUDefault      = "1000m"
 	LimitsMemoryDefault   = "1024M"
 
-	SvcNamePrefix            = "service"
-	SvcProcessesDefault      = 1
-	SvcReadinessProbeDefault = 2
+	SvcVerticalScalingModeDefault             = "Off"
+	SvcVerticalScalingControlledValuesDefault = "RequestsAndLimits"
+
+	SvcNamePrefix             = "service"
+	SvcProcessesDefault       = 1
+	SvcReadinessProbeDefault  = 2
+	SvcReadinessPeriodDefault = 1
+	SvcStorageSizeDefault     = "1Gi"
+
+	SvcAutoscalingKindDefault        = "hpa"
+	SvcAutoscalingMinReplicasDefault = 1
+	SvcAutoscalingCpuDefault         = 80
+	SvcAutoscalingPrometheusDefault  = "http://prometheus.istio-system:9090"
 
 	EpNamePrefix            = "end"
 	EpExecModeDefault       = "sequential"
 	EpNwResponseSizeDefault = 512
 
 	EpExecTimeDefault = 0.001
+	EpCpuModeDefault  = "time"
+
+	EpCpuKernelDefault         = "busy"
+	EpCpuWorkingSetSizeDefault = 65536
+
+	EpLockLocksDefault    = 1
+	EpLockHoldModeDefault = "cpu"
+
+	EpPayloadContentTypeDefault = "text"
+	EpPayloadTransformDefault   = "none"
+
+	EpStorageOperationsDefault   = 1
+	EpStorageDistributionDefault = "uniform"
+	EpStorageZipfExponentDefault = 1.1
 
 	EpNwForwardRequests = "asynchronous"
 
 	CsTrafficForwardRatio = 1
 	CsRequestSizeDefault  = 256
+
+	CsMaxHedgesDefault = 1
+
+	LoadGeneratorName    = "hydragen-loadgen"
+	LoadGeneratorCommand = "/usr/bin/app-generator"
+
+	WlKindDefault         = "job"
+	WlPatternDefault      = "closed"
+	WlDurationDefault     = 60
+	WlUsersDefault        = 1
+	WlRateDefault         = 10
+	WlStepDurationDefault = 10
+	WlMaxInFlightDefault  = 1000
+	WlTimeoutDefault      = 10
+	WlIntervalDefault     = 1
+	WlFormatDefault       = "csv"
+	WlWeightDefault       = 1
+
+	MeshNetworkingAPIVersion = "networking.istio.io/v1beta1"
+	MeshSecurityAPIVersion   = "security.istio.io/v1beta1"
+	MeshInjectionLabel       = "istio-injection"
+	MeshMtlsDefault          = "PERMISSIVE"
+	NamespacesFileName       = "namespaces.yaml"
+
+	DiscoveryMcs           = "mcs"
+	DiscoveryExternal      = "external"
+	DiscoveryMcsAPIVersion = "multicluster.x-k8s.io/v1alpha1"
+	DiscoveryMcsDomain     = "svc.clusterset.local"
+
+	NamespaceNameLabel = "kubernetes.io/metadata.name"
+
+	ProbeLivenessPath    = "/healthz/live"
+	ProbeStartupPath     = "/healthz/startup"
+	ProbeLivenessService = "liveness"
+	ProbeStartupService  = "startup"
+
+	EntryPointKindDefault = "ingress"
+	GatewayAPIVersion     = "gateway.networking.k8s.io/v1"
+	GrpcPackage           = "generated"
+
+	RolloutStrategyType    = "RollingUpdate"
+	RolloutV1Name          = "v1"
+	RolloutV2Name          = "v2"
+	RolloutV2FactorDefault = 2
+	// The route of the entry point endpoints of a service already has its name
+	RolloutRouteSuffix = "-versions"
+
+	ClusterLabel = "cluster"
+	VersionLabel = "version"
 )
 
 func HostnameFQDN() string {
@@ -86,10 +180,35 @@ func HostnameFQDN() string {
 	return strings.TrimSpace(out.String())
 }
 
-func CreateDeployment(metadataName, selectorAppName, selectorClusterName string, numberOfReplicas int,
-	templateAppLabel, templateClusterLabel, namespace string, port int, containerName, containerImageURL, containerImagePolicy,
-	mountPath string, volumeName, configMapName string, readinessProbe int, requestCPU, requestMemory, limitCPU,
-	limitMemory, nodeAffinity, protocol string, annotations []model.Annotation) (deploymentInstance model.DeploymentInstance) {
+// Returns a memory quantity such as 1024M in bytes
+func memoryBytes(memory string) int64 {
+	memoryResource, _ := resource.ParseQuantity(memory)
+	value, ok := memoryResource.AsInt64()
+	if !ok {
+		panic(fmt.Errorf("Could not parse memory %s as bytes", memory))
+	}
+	return value
+}
+
+// Returns a CPU quantity such as 500m as a number of CPUs, or an empty string if it is not set
+func cpus(cpu string) string {
+	if cpu == "" {
+		return ""
+	}
+	cpuResource, err := resource.ParseQuantity(cpu)
+	if err != nil {
+		panic(fmt.Errorf("Could not parse CPU limit %s: %s", cpu, err))
+	}
+	return strconv.FormatFloat(cpuResource.AsApproximateFloat64(), 'f', -1, 64)
+}
+
+// Creates the Deployment of a service
+// The pods of a service with versions are labeled with their version, so that the Deployment of every version selects only its own pods
+func CreateDeployment(metadataName, selectorAppName, selectorClusterName string, numberOfReplicas int, strategy *model.DeploymentStrategyInstance,
+	templateAppLabel, templateClusterLabel, version, namespace string, port int, containerName, containerImageURL, containerImagePolicy,
+	mountPath string, volumeName, configMapName string, probes model.ProbesInstance, requestCPU, requestMemory, limitCPU,
+	limitMemory, nodeAffinity string, annotations []model.Annotation,
+	scheduling model.SchedulingInstance) (deploymentInstance model.DeploymentInstance) {
 
 	var deployment model.DeploymentInstance
 	var containerInstance model.ContainerInstance
@@ -99,19 +218,16 @@ func CreateDeployment(metadataName, selectorAppName, selectorClusterName string,
 	var volumeInstance model.VolumeInstance
 
 	serviceEnvInstance.Name = "SERVICE_NAME"
-	serviceEnvInstance.Value = metadataName
+	serviceEnvInstance.Value = templateAppLabel
 	containerInstance.Env = append(containerInstance.Env, serviceEnvInstance)
 
-	memlimitResource, _ := resource.ParseQuantity(limitMemory)
-	memlimitBytes, ok := memlimitResource.AsInt64()
-	if !ok {
-		panic(fmt.Errorf("Could not parse memory limit %s as bytes", limitMemory))
+	// Pods without a memory limit leave the garbage collector at its default
+	if limitMemory != "" {
+		memlimitEnvInstance.Name = "GOMEMLIMIT"
+		memlimitEnvInstance.Value = fmt.Sprint(memoryBytes(limitMemory))
+		containerInstance.Env = append(containerInstance.Env, memlimitEnvInstance)
 	}
 
-	memlimitEnvInstance.Name = "GOMEMLIMIT"
-	memlimitEnvInstance.Value = fmt.Sprint(memlimitBytes)
-	containerInstance.Env = append(containerInstance.Env, memlimitEnvInstance)
-
 	volumeInstance.Name = volumeName
 	volumeInstance.ConfigMap.Name = configMapName
 
@@ -124,15 +240,7 @@ func CreateDeployment(metadataName, selectorAppName, selectorClusterName string,
 	containerInstance.Image = containerImageURL
 	containerInstance.ImagePullPolicy = containerImagePolicy
 
-	if protocol == "http" {
-		containerInstance.ReadinessProbe.HttpGet.Path = "/"
-		containerInstance.ReadinessProbe.HttpGet.Port = port
-	} else if protocol == "grpc" {
-		containerInstance.ReadinessProbe.Exec.Command = append(containerInstance.ReadinessProbe.Exec.Command, ("/usr/bin/grpc_health_probe"), "-addr=:"+strconv.Itoa(port))
-	}
-
-	containerInstance.ReadinessProbe.InitialDelaySeconds = readinessProbe
-	containerInstance.ReadinessProbe.PeriodSeconds = 1
+	containerInstance.ProbesInstance = probes
 	containerInstance.Resources.ResourceRequests.Cpu = requestCPU
 	containerInstance.Resources.ResourceRequests.Memory = requestMemory
 	containerInstance.Resources.ResourceLimits.Cpu = limitCPU
@@ -145,9 +253,12 @@ func CreateDeployment(metadataName, selectorAppName, selectorClusterName string,
 	deployment.Metadata.Labels.Cluster = templateClusterLabel
 	deployment.Spec.Selector.MatchLabels.App = selectorAppName
 	deployment.Spec.Selector.MatchLabels.Cluster = selectorClusterName
+	deployment.Spec.Selector.MatchLabels.Version = version
 	deployment.Spec.Replicas = numberOfReplicas
+	deployment.Spec.Strategy = strategy
 	deployment.Spec.Template.Metadata.Labels.App = templateAppLabel
 	deployment.Spec.Template.Metadata.Labels.Cluster = templateClusterLabel
+	deployment.Spec.Template.Metadata.Labels.Version = version
 	if len(annotations) > 0 {
 		deployment.Spec.Template.Metadata.Annotations = map[string]string{}
 		for i := 0; i < len(annotations); i++ {
@@ -158,11 +269,165 @@ func CreateDeployment(metadataName, selectorAppName, selectorClusterName string,
 	deployment.Spec.Template.Spec.Containers = append(deployment.Spec.Template.Spec.Containers, containerInstance)
 	deployment.Spec.Template.Spec.Volumes = append(deployment.Spec.Template.Spec.Volumes, volumeInstance)
 	deployment.Spec.Template.Spec.NodeName = nodeAffinity
+	deployment.Spec.Template.Spec.SchedulingInstance = scheduling
 
 	return deployment
 
 }
 
+// Creates the scheduling constraints of the pods of a service on a cluster
+// namespaces contains the namespace of every service on the cluster, which selects the pods of services in other namespaces
+func CreateScheduling(selectorAppName string, cluster model.Cluster, namespaces map[string]string) (schedulingInstance model.SchedulingInstance) {
+	var scheduling model.SchedulingInstance
+
+	scheduling.NodeSelector = cluster.NodeSelector
+	scheduling.PriorityClassName = cluster.PriorityClass
+
+	var nodeAffinity model.NodeAffinityInstance
+	required := model.NodeSelectorTermInstance{}
+	for _, rule := range cluster.NodeAffinity {
+		requirement := model.NodeSelectorRequirementInstance{Key: rule.Key, Operator: rule.Operator, Values: rule.Values}
+		if rule.Weight == 0 {
+			required.MatchExpressions = append(required.MatchExpressions, requirement)
+		} else {
+			nodeAffinity.Preferred = append(nodeAffinity.Preferred, model.PreferredSchedulingTermInstance{
+				Weight:     rule.Weight,
+				Preference: model.NodeSelectorTermInstance{MatchExpressions: []model.NodeSelectorRequirementInstance{requirement}},
+			})
+		}
+	}
+	// All required rules are in one term, so that a node has to match all of them
+	if len(required.MatchExpressions) > 0 {
+		nodeAffinity.Required = &model.NodeSelectorInstance{NodeSelectorTerms: []model.NodeSelectorTermInstance{required}}
+	}
+
+	podAffinity := func(rules []model.ServiceAffinityRule) *model.PodAffinityInstance {
+		if len(rules) == 0 {
+			return nil
+		}
+
+		var affinity model.PodAffinityInstance
+		for _, rule := range rules {
+			term := model.PodAffinityTermInstance{
+				LabelSelector: model.LabelSelectorInstance{MatchLabels: map[string]string{"app": rule.Service}},
+				TopologyKey:   rule.TopologyKey,
+			}
+			if namespace, ok := namespaces[rule.Service]; ok && namespace != cluster.Namespace {
+				term.Namespaces = []string{namespace}
+			}
+
+			if rule.Weight == 0 {
+				affinity.Required = append(affinity.Required, term)
+			} else {
+				affinity.Preferred = append(affinity.Preferred, model.WeightedPodAffinityTermInstance{Weight: rule.Weight, PodAffinityTerm: term})
+			}
+		}
+		return &affinity
+	}
+
+	affinity := model.AffinityInstance{
+		PodAffinity:     podAffinity(cluster.ServiceAffinity),
+		PodAntiAffinity: podAffinity(cluster.ServiceAntiAffinity),
+	}
+	if nodeAffinity.Required != nil || len(nodeAffinity.Preferred) > 0 {
+		affinity.NodeAffinity = &nodeAffinity
+	}
+	if affinity.NodeAffinity != nil || affinity.PodAffinity != nil || affinity.PodAntiAffinity != nil {
+		scheduling.Affinity = &affinity
+	}
+
+	for _, toleration := range cluster.Tolerations {
+		scheduling.Tolerations = append(scheduling.Tolerations, model.TolerationInstance{
+			Key:               toleration.Key,
+			Operator:          toleration.Operator,
+			Value:             toleration.Value,
+			Effect:            toleration.Effect,
+			TolerationSeconds: toleration.TolerationSeconds,
+		})
+	}
+
+	for _, spread := range cluster.TopologySpread {
+		scheduling.TopologySpreadConstraints = append(scheduling.TopologySpreadConstraints, model.TopologySpreadConstraintInstance{
+			MaxSkew:           spread.MaxSkew,
+			TopologyKey:       spread.TopologyKey,
+			WhenUnsatisfiable: spread.WhenUnsatisfiable,
+			LabelSelector:     model.LabelSelectorInstance{MatchLabels: map[string]string{"app": selectorAppName}},
+		})
+	}
+
+	return scheduling
+}
+
+// Creates a probe that checks a health path of an HTTP service or a health service of a gRPC service
+// Readiness is checked at the root path and the default health service
+func createProbe(port int, protocol, path, grpcService string, probe *model.Probe) *model.ProbeInstance {
+	var probeInstance model.ProbeInstance
+
+	if protocol == "http" {
+		probeInstance.HttpGet.Path = path
+		probeInstance.HttpGet.Port = port
+	} else if protocol == "grpc" {
+		probeInstance.Exec.Command = append(probeInstance.Exec.Command, "/usr/bin/grpc_health_probe", "-addr=:"+strconv.Itoa(port))
+		if grpcService != "" {
+			probeInstance.Exec.Command = append(probeInstance.Exec.Command, "-service="+grpcService)
+		}
+	}
+
+	probeInstance.InitialDelaySeconds = probe.InitialDelay
+	probeInstance.PeriodSeconds = probe.Period
+	probeInstance.TimeoutSeconds = probe.Timeout
+	probeInstance.FailureThreshold = probe.FailureThreshold
+	probeInstance.SuccessThreshold = probe.SuccessThreshold
+
+	return &probeInstance
+}
+
+// Creates the readiness probe of a service and its liveness and startup probes if they are set
+func CreateProbes(port int, protocol string, probes model.Probes) (probesInstance model.ProbesInstance) {
+	readiness := probes.Readiness
+	if readiness == nil {
+		readiness = &model.Probe{}
+	}
+
+	probesInstance.ReadinessProbe = *createProbe(port, protocol, "/", "", readiness)
+	if probes.Liveness != nil {
+		probesInstance.LivenessProbe = createProbe(port, protocol, ProbeLivenessPath, ProbeLivenessService, probes.Liveness)
+	}
+	if probes.Startup != nil {
+		probesInstance.StartupProbe = createProbe(port, protocol, ProbeStartupPath, ProbeStartupService, probes.Startup)
+	}
+
+	return probesInstance
+}
+
+// Turns a deployment into a StatefulSet where every pod has its own persistent volume mounted at mountPath
+func CreateStatefulSet(deployment model.DeploymentInstance, serviceName, volumeName, mountPath, size, storageClass string) (statefulSetInstance model.DeploymentInstance) {
+	var claim model.PersistentVolumeClaimInstance
+
+	claim.Metadata.Name = volumeName
+	claim.Spec.AccessModes = []string{"ReadWriteOnce"}
+	claim.Spec.StorageClassName = storageClass
+	claim.Spec.Resources.Requests.Storage = size
+
+	deployment.Kind = "StatefulSet"
+	deployment.Spec.ServiceName = serviceName
+	// StatefulSets replace their pods without creating additional ones
+	if deployment.Spec.Strategy != nil {
+		updateStrategy := *deployment.Spec.Strategy
+		updateStrategy.RollingUpdate.MaxSurge = nil
+		deployment.Spec.Strategy = nil
+		deployment.Spec.UpdateStrategy = &updateStrategy
+	}
+	deployment.Spec.VolumeClaimTemplates = append(deployment.Spec.VolumeClaimTemplates, claim)
+
+	for i := range deployment.Spec.Template.Spec.Containers {
+		container := &deployment.Spec.Template.Spec.Containers[i]
+		container.Volumes = append(container.Volumes, model.ContainerVolumeInstance{MountName: volumeName, MountPath: mountPath})
+	}
+
+	return deployment
+}
+
 func CreateWorkerDeployment(metadataName, selectorName string, numberOfReplicas int, templateLabel string,
 	containerName, containerImageURL, containerImagePolicy, mountPath string, volumeName, configMapName string) (deploymentInstance model.DeploymentInstance) {
 
@@ -194,7 +459,105 @@ func CreateWorkerDeployment(metadataName, selectorName string, numberOfReplicas
 	return deployment
 }
 
-func CreateService(metadataName, selectorAppName, protocol, uri, metadataLabelCluster, namespace string, ports []model.ServicePortInstance) (serviceInstance model.ServiceInstance) {
+// Creates the container of the load generator, which runs command with args instead of the emulator
+func CreateLoadGeneratorContainer(containerName, containerImageURL, containerImagePolicy, mountPath, volumeName string,
+	command, args []string) (containerInstance model.WorkerContainerInstance) {
+
+	var container model.WorkerContainerInstance
+
+	container.Name = containerName
+	container.Image = containerImageURL
+	container.ImagePullPolicy = containerImagePolicy
+	container.Command = command
+	container.Args = args
+	container.Volumes = append(container.Volumes, model.ContainerVolumeInstance{MountName: volumeName, MountPath: mountPath})
+
+	return container
+}
+
+// Creates a Job that runs the load generator container once
+func CreateLoadGeneratorJob(metadataName, metadataLabelCluster, namespace string, container model.WorkerContainerInstance,
+	volumeName, configMapName string) (jobInstance model.WorkerJobInstance) {
+
+	var job model.WorkerJobInstance
+	var volumeInstance model.VolumeInstance
+
+	volumeInstance.Name = volumeName
+	volumeInstance.ConfigMap.Name = configMapName
+
+	job.APIVersion = "batch/v1"
+	job.Kind = "Job"
+	job.Metadata.Name = metadataName
+	job.Metadata.Namespace = namespace
+	job.Metadata.Labels = clusterLabels(metadataLabelCluster)
+	job.Spec.BackoffLimit = 0
+	job.Spec.Template.Metadata.Labels.App = metadataName
+	job.Spec.Template.Metadata.Labels.Cluster = metadataLabelCluster
+	job.Spec.Template.Spec.RestartPolicy = "Never"
+	job.Spec.Template.Spec.Containers = append(job.Spec.Template.Spec.Containers, container)
+	job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, volumeInstance)
+
+	return job
+}
+
+// Creates a Deployment that keeps the load generator container running
+func CreateLoadGeneratorDeployment(metadataName, metadataLabelCluster, namespace string, container model.WorkerContainerInstance,
+	volumeName, configMapName string) (deploymentInstance model.WorkerDeploymentInstance) {
+
+	var deployment model.WorkerDeploymentInstance
+	var volumeInstance model.VolumeInstance
+
+	volumeInstance.Name = volumeName
+	volumeInstance.ConfigMap.Name = configMapName
+
+	deployment.APIVersion = "apps/v1"
+	deployment.Kind = "Deployment"
+	deployment.Metadata.Name = metadataName
+	deployment.Metadata.Namespace = namespace
+	deployment.Metadata.Labels = clusterLabels(metadataLabelCluster)
+	deployment.Spec.Selector.MatchLabels.App = metadataName
+	deployment.Spec.Replicas = 1
+	deployment.Spec.Template.Metadata.Labels.App = metadataName
+	deployment.Spec.Template.Metadata.Labels.Cluster = metadataLabelCluster
+	deployment.Spec.Template.Spec.Containers = append(deployment.Spec.Template.Spec.Containers, container)
+	deployment.Spec.Template.Spec.Volumes = append(deployment.Spec.Template.Spec.Volumes, volumeInstance)
+
+	return deployment
+}
+
+// Creates a Docker Compose service running one replica of a service
+// The config file is mounted from the host and other services reach it through the aliases on the network
+func CreateComposeService(serviceName, containerImageURL, configFile, mountPath, networkName string, aliases []string,
+	hostPort, port int, requestCPU, requestMemory, limitCPU, limitMemory string) (composeServiceInstance model.ComposeServiceInstance) {
+
+	var composeService model.ComposeServiceInstance
+
+	composeService.Image = containerImageURL
+	composeService.PullPolicy = "never"
+	composeService.Environment = map[string]string{
+		"SERVICE_NAME": serviceName,
+	}
+	composeService.Ports = append(composeService.Ports, fmt.Sprintf("%d:%d", hostPort, port))
+	composeService.Volumes = append(composeService.Volumes, fmt.Sprintf("%s:%s/conf.json:ro", configFile, mountPath))
+	composeService.Networks = map[string]model.ComposeServiceNetworkInstance{
+		networkName: {Aliases: aliases},
+	}
+	composeService.Deploy.Resources.Limits.Cpus = cpus(limitCPU)
+	composeService.Deploy.Resources.Reservations.Cpus = cpus(requestCPU)
+	if limitMemory != "" {
+		composeService.Environment["GOMEMLIMIT"] = fmt.Sprint(memoryBytes(limitMemory))
+		composeService.Deploy.Resources.Limits.Memory = fmt.Sprint(memoryBytes(limitMemory))
+	}
+	if requestMemory != "" {
+		composeService.Deploy.Resources.Reservations.Memory = fmt.Sprint(memoryBytes(requestMemory))
+	}
+
+	return composeService
+}
+
+// Creates the Service of a service, or of one of its versions if selectorVersion is set
+func CreateService(metadataName, selectorAppName, selectorVersion, protocol, uri, metadataLabelCluster, namespace, serviceType string,
+	ports []model.ServicePortInstance) (serviceInstance model.ServiceInstance) {
 	const apiVersion = "v1"
 	const apiKind = "Service"
 
@@ -211,11 +574,524 @@ func CreateService(metadataName, selectorAppName, protocol, uri, metadataLabelCl
 	service.Metadata.Labels.Cluster = metadataLabelCluster
 	service.Metadata.Annotations = annotations
 	service.Spec.Selector.App = selectorAppName
+	service.Spec.Selector.Version = selectorVersion
 	service.Spec.Ports = append(service.Spec.Ports, ports...)
+	service.Spec.Type = serviceType
 
 	return service
 }
 
+// Creates the headless Service that governs the StatefulSet of a service, which gives every pod a stable DNS name
+func CreateHeadlessService(metadataName, selectorAppName, protocol, uri, metadataLabelCluster, namespace string,
+	ports []model.ServicePortInstance) (serviceInstance model.ServiceInstance) {
+
+	service := CreateService(metadataName, selectorAppName, "", protocol, uri, metadataLabelCluster, namespace, "", ports)
+	service.Spec.ClusterIP = "None"
+
+	return service
+}
+
+// Returns a duration in seconds in the format used by Istio, or an empty string if it is not set
+func duration(seconds float64) string {
+	if seconds <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(seconds, 'f', -1, 64) + "s"
+}
+
+// Returns the cluster label of a manifest, or no labels if the cluster is not set
+func clusterLabels(cluster string) map[string]string {
+	if cluster == "" {
+		return nil
+	}
+	return map[string]string{ClusterLabel: cluster}
+}
+
+func CreateNamespace(metadataName string, labels map[string]string) (namespaceInstance model.NamespaceInstance) {
+	var namespace model.NamespaceInstance
+
+	namespace.APIVersion = "v1"
+	namespace.Kind = "Namespace"
+	namespace.Metadata.Name = metadataName
+	namespace.Metadata.Labels = labels
+
+	return namespace
+}
+
+// Creates a VirtualService that routes all requests for host to the port of the service with a timeout and retries
+// Requests are split between the subsets of the versions with a weight, or sent to all pods of the service if no version has one
+func CreateVirtualService(metadataName, metadataLabelCluster, namespace, host string, port int, timeout float64,
+	retries *model.Retries, versions []model.Version) (virtualServiceInstance model.VirtualServiceInstance) {
+
+	var virtualService model.VirtualServiceInstance
+
+	destinations := []model.VirtualServiceDestinationInstance{}
+	for _, version := range versions {
+		if version.Weight > 0 {
+			var destination model.VirtualServiceDestinationInstance
+			destination.Destination.Host = host
+			destination.Destination.Subset = version.Name
+			destination.Destination.Port.Number = port
+			destination.Weight = version.Weight
+			destinations = append(destinations, destination)
+		}
+	}
+	if len(destinations) == 0 {
+		var destination model.VirtualServiceDestinationInstance
+		destination.Destination.Host = host
+		destination.Destination.Port.Number = port
+		destinations = append(destinations, destination)
+	}
+
+	route := model.VirtualServiceRouteInstance{
+		Route:   destinations,
+		Timeout: duration(timeout),
+	}
+	if retries != nil {
+		route.Retries = &model.VirtualServiceRetriesInstance{
+			Attempts:      retries.Attempts,
+			PerTryTimeout: duration(retries.PerTryTimeout),
+			RetryOn:       retries.RetryOn,
+		}
+	}
+
+	virtualService.APIVersion = MeshNetworkingAPIVersion
+	virtualService.Kind = "VirtualService"
+	virtualService.Metadata.Name = metadataName
+	virtualService.Metadata.Namespace = namespace
+	virtualService.Metadata.Labels = clusterLabels(metadataLabelCluster)
+	virtualService.Spec.Hosts = []string{host}
+	virtualService.Spec.Http = append(virtualService.Spec.Http, route)
+
+	return virtualService
+}
+
+// Creates a DestinationRule with the load balancing, connection pool and outlier detection settings of a mesh policy
+// Every version gets a subset that selects its pods
+func CreateDestinationRule(metadataName, metadataLabelCluster, namespace, host, loadBalancer string,
+	connectionPool *model.ConnectionPool, outlierDetection *model.OutlierDetection, versions []string) (destinationRuleInstance model.DestinationRuleInstance) {
+
+	var destinationRule model.DestinationRuleInstance
+	trafficPolicy := &destinationRule.Spec.TrafficPolicy
+
+	if loadBalancer != "" {
+		trafficPolicy.LoadBalancer = &model.LoadBalancerInstance{Simple: loadBalancer}
+	}
+	if connectionPool != nil {
+		trafficPolicy.ConnectionPool = &model.ConnectionPoolInstance{}
+		trafficPolicy.ConnectionPool.Tcp.MaxConnections = connectionPool.MaxConnections
+		trafficPolicy.ConnectionPool.Tcp.ConnectTimeout = duration(connectionPool.ConnectTimeout)
+		trafficPolicy.ConnectionPool.Http.Http1MaxPendingRequests = connectionPool.MaxPendingRequests
+		trafficPolicy.ConnectionPool.Http.Http2MaxRequests = connectionPool.MaxRequests
+		trafficPolicy.ConnectionPool.Http.MaxRequestsPerConnection = connectionPool.MaxRequestsPerConnection
+	}
+	if outlierDetection != nil {
+		trafficPolicy.OutlierDetection = &model.OutlierDetectionInstance{
+			Consecutive5xxErrors: outlierDetection.ConsecutiveErrors,
+			Interval:             duration(outlierDetection.Interval),
+			BaseEjectionTime:     duration(outlierDetection.BaseEjectionTime),
+			MaxEjectionPercent:   outlierDetection.MaxEjectionPercent,
+		}
+	}
+
+	destinationRule.APIVersion = MeshNetworkingAPIVersion
+	destinationRule.Kind = "DestinationRule"
+	destinationRule.Metadata.Name = metadataName
+	destinationRule.Metadata.Namespace = namespace
+	destinationRule.Metadata.Labels = clusterLabels(metadataLabelCluster)
+	destinationRule.Spec.Host = host
+	for _, version := range versions {
+		destinationRule.Spec.Subsets = append(destinationRule.Spec.Subsets,
+			model.DestinationSubsetInstance{Name: version, Labels: map[string]string{VersionLabel: version}})
+	}
+
+	return destinationRule
+}
+
+// Creates a PeerAuthentication that sets the mutual TLS mode of the pods of a service
+func CreatePeerAuthentication(metadataName, selectorAppName, metadataLabelCluster, namespace,
+	mode string) (peerAuthenticationInstance model.PeerAuthenticationInstance) {
+
+	var peerAuthentication model.PeerAuthenticationInstance
+
+	peerAuthentication.APIVersion = MeshSecurityAPIVersion
+	peerAuthentication.Kind = "PeerAuthentication"
+	peerAuthentication.Metadata.Name = metadataName
+	peerAuthentication.Metadata.Namespace = namespace
+	peerAuthentication.Metadata.Labels = clusterLabels(metadataLabelCluster)
+	peerAuthentication.Spec.Selector.MatchLabels = map[string]string{"app": selectorAppName}
+	peerAuthentication.Spec.Mtls.Mode = mode
+
+	return peerAuthentication
+}
+
+// Creates a Service that resolves to a DNS name, such as the name of a service in another namespace or cluster
+func CreateExternalNameService(metadataName, metadataLabelCluster, namespace,
+	externalName string) (externalNameServiceInstance model.ExternalNameServiceInstance) {
+
+	var service model.ExternalNameServiceInstance
+
+	service.APIVersion = "v1"
+	service.Kind = "Service"
+	service.Metadata.Name = metadataName
+	service.Metadata.Namespace = namespace
+	service.Metadata.Labels = clusterLabels(metadataLabelCluster)
+	service.Spec.Type = "ExternalName"
+	service.Spec.ExternalName = externalName
+
+	return service
+}
+
+// Creates a ServiceExport that makes a service reachable from the other clusters of a cluster set
+func CreateServiceExport(metadataName, metadataLabelCluster, namespace string) (serviceExportInstance model.ServiceExportInstance) {
+	var serviceExport model.ServiceExportInstance
+
+	serviceExport.APIVersion = DiscoveryMcsAPIVersion
+	serviceExport.Kind = "ServiceExport"
+	serviceExport.Metadata.Name = metadataName
+	serviceExport.Metadata.Namespace = namespace
+	serviceExport.Metadata.Labels = clusterLabels(metadataLabelCluster)
+
+	return serviceExport
+}
+
+// Returns a number of pods as an integer, since Kubernetes only accepts strings that are percentages
+func intOrPercent(value string) interface{} {
+	if number, err := strconv.Atoi(value); err == nil {
+		return number
+	}
+	return value
+}
+
+// Creates the rolling update strategy of a deployment, or nil if it uses the default strategy
+func CreateDeploymentStrategy(maxUnavailable, maxSurge string) *model.DeploymentStrategyInstance {
+	if maxUnavailable == "" && maxSurge == "" {
+		return nil
+	}
+
+	var strategy model.DeploymentStrategyInstance
+
+	strategy.Type = RolloutStrategyType
+	if maxUnavailable != "" {
+		strategy.RollingUpdate.MaxUnavailable = intOrPercent(maxUnavailable)
+	}
+	if maxSurge != "" {
+		strategy.RollingUpdate.MaxSurge = intOrPercent(maxSurge)
+	}
+
+	return &strategy
+}
+
+// Creates a PodDisruptionBudget that limits how many pods of a service are evicted at once
+func CreatePodDisruptionBudget(metadataName, selectorAppName, metadataLabelCluster, namespace,
+	minAvailable, maxUnavailable string) (podDisruptionBudgetInstance model.PodDisruptionBudgetInstance) {
+
+	var podDisruptionBudget model.PodDisruptionBudgetInstance
+
+	podDisruptionBudget.APIVersion = "policy/v1"
+	podDisruptionBudget.Kind = "PodDisruptionBudget"
+	podDisruptionBudget.Metadata.Name = metadataName
+	podDisruptionBudget.Metadata.Namespace = namespace
+	podDisruptionBudget.Metadata.Labels = clusterLabels(metadataLabelCluster)
+	podDisruptionBudget.Spec.Selector.MatchLabels = map[string]string{"app": selectorAppName}
+	if minAvailable != "" {
+		podDisruptionBudget.Spec.MinAvailable = intOrPercent(minAvailable)
+	}
+	if maxUnavailable != "" {
+		podDisruptionBudget.Spec.MaxUnavailable = intOrPercent(maxUnavailable)
+	}
+
+	return podDisruptionBudget
+}
+
+// Creates a peer of a NetworkPolicy that selects the pods of a service, in another namespace if namespace is set
+func CreateNetworkPolicyPeer(selectorAppName, namespace string) (networkPolicyPeerInstance model.NetworkPolicyPeerInstance) {
+	var peer model.NetworkPolicyPeerInstance
+
+	peer.PodSelector = &model.LabelSelectorInstance{MatchLabels: map[string]string{"app": selectorAppName}}
+	if namespace != "" {
+		peer.NamespaceSelector = &model.LabelSelectorInstance{MatchLabels: map[string]string{NamespaceNameLabel: namespace}}
+	}
+
+	return peer
+}
+
+// Creates a NetworkPolicy that only allows traffic to a port of the pods of a service from the peers
+// With anySource, traffic to the port is allowed from everywhere, and without peers all traffic is denied
+func CreateNetworkPolicy(metadataName, selectorAppName, metadataLabelCluster, namespace string, port int,
+	peers []model.NetworkPolicyPeerInstance, anySource bool) (networkPolicyInstance model.NetworkPolicyInstance) {
+
+	var networkPolicy model.NetworkPolicyInstance
+
+	networkPolicy.APIVersion = "networking.k8s.io/v1"
+	networkPolicy.Kind = "NetworkPolicy"
+	networkPolicy.Metadata.Name = metadataName
+	networkPolicy.Metadata.Namespace = namespace
+	networkPolicy.Metadata.Labels = clusterLabels(metadataLabelCluster)
+	networkPolicy.Spec.PodSelector.MatchLabels = map[string]string{"app": selectorAppName}
+	networkPolicy.Spec.PolicyTypes = []string{"Ingress"}
+	networkPolicy.Spec.Ingress = []model.NetworkPolicyIngressRuleInstance{}
+
+	ports := []model.NetworkPolicyPortInstance{{Protocol: "TCP", Port: port}}
+	if anySource {
+		networkPolicy.Spec.Ingress = append(networkPolicy.Spec.Ingress, model.NetworkPolicyIngressRuleInstance{Ports: ports})
+	} else if len(peers) > 0 {
+		networkPolicy.Spec.Ingress = append(networkPolicy.Spec.Ingress, model.NetworkPolicyIngressRuleInstance{From: peers, Ports: ports})
+	}
+
+	return networkPolicy
+}
+
+// Creates an Ingress that routes requests to the paths of a service, for any host if host is not set
+func CreateIngress(metadataName, metadataLabelCluster, namespace, className, host, serviceName string, port int,
+	paths []string) (ingressInstance model.IngressInstance) {
+
+	var ingress model.IngressInstance
+
+	ingress.APIVersion = "networking.k8s.io/v1"
+	ingress.Kind = "Ingress"
+	ingress.Metadata.Name = metadataName
+	ingress.Metadata.Namespace = namespace
+	ingress.Metadata.Labels = clusterLabels(metadataLabelCluster)
+	ingress.Spec.IngressClassName = className
+
+	var rule model.IngressRuleInstance
+	rule.Host = host
+	for _, path := range paths {
+		var ingressPath model.IngressPathInstance
+		ingressPath.Path = path
+		ingressPath.PathType = "Exact"
+		ingressPath.Backend.Service.Name = serviceName
+		ingressPath.Backend.Service.Port.Number = port
+		rule.Http.Paths = append(rule.Http.Paths, ingressPath)
+	}
+	ingress.Spec.Rules = append(ingress.Spec.Rules, rule)
+
+	return ingress
+}
+
+// Returns the Gateway a route is attached to and the hostnames it matches, or no hostnames if host is not set
+func gatewayRoute(gateway, gatewayNamespace, host string) ([]model.GatewayParentRefInstance, []string) {
+	parentRefs := []model.GatewayParentRefInstance{{Name: gateway, Namespace: gatewayNamespace}}
+	if host == "" {
+		return parentRefs, nil
+	}
+	return parentRefs, []string{host}
+}
+
+// Creates an HTTPRoute that attaches the paths of a service to a Gateway
+func CreateHTTPRoute(metadataName, metadataLabelCluster, namespace, gateway, gatewayNamespace, host string,
+	backendRefs []model.GatewayBackendRefInstance, paths []string) (httpRouteInstance model.HTTPRouteInstance) {
+
+	var httpRoute model.HTTPRouteInstance
+
+	httpRoute.APIVersion = GatewayAPIVersion
+	httpRoute.Kind = "HTTPRoute"
+	httpRoute.Metadata.Name = metadataName
+	httpRoute.Metadata.Namespace = namespace
+	httpRoute.Metadata.Labels = clusterLabels(metadataLabelCluster)
+	httpRoute.Spec.ParentRefs, httpRoute.Spec.Hostnames = gatewayRoute(gateway, gatewayNamespace, host)
+
+	var rule model.HTTPRouteRuleInstance
+	for _, path := range paths {
+		var match model.HTTPRouteMatchInstance
+		match.Path.Type = "Exact"
+		match.Path.Value = path
+		rule.Matches = append(rule.Matches, match)
+	}
+	rule.BackendRefs = backendRefs
+	httpRoute.Spec.Rules = append(httpRoute.Spec.Rules, rule)
+
+	return httpRoute
+}
+
+// Creates a GRPCRoute that attaches the methods of a gRPC service to a Gateway
+func CreateGRPCRoute(metadataName, metadataLabelCluster, namespace, gateway, gatewayNamespace, host string,
+	backendRefs []model.GatewayBackendRefInstance, grpcService string, methods []string) (grpcRouteInstance model.GRPCRouteInstance) {
+
+	var grpcRoute model.GRPCRouteInstance
+
+	grpcRoute.APIVersion = GatewayAPIVersion
+	grpcRoute.Kind = "GRPCRoute"
+	grpcRoute.Metadata.Name = metadataName
+	grpcRoute.Metadata.Namespace = namespace
+	grpcRoute.Metadata.Labels = clusterLabels(metadataLabelCluster)
+	grpcRoute.Spec.ParentRefs, grpcRoute.Spec.Hostnames = gatewayRoute(gateway, gatewayNamespace, host)
+
+	var rule model.GRPCRouteRuleInstance
+	for _, method := range methods {
+		var match model.GRPCRouteMatchInstance
+		match.Method.Service = grpcService
+		match.Method.Method = method
+		rule.Matches = append(rule.Matches, match)
+	}
+	rule.BackendRefs = backendRefs
+	grpcRoute.Spec.Rules = append(grpcRoute.Spec.Rules, rule)
+
+	return grpcRoute
+}
+
+// Returns the Service a route of a service mesh is attached to, which has the core API group
+func serviceRoute(serviceName string, port int) []model.GatewayParentRefInstance {
+	group := ""
+	return []model.GatewayParentRefInstance{{Group: &group, Kind: "Service", Name: serviceName, Port: port}}
+}
+
+// Creates an HTTPRoute that splits the requests to the Service of a service between backends in a service mesh
+func CreateServiceHTTPRoute(metadataName, metadataLabelCluster, namespace, serviceName string, port int,
+	backendRefs []model.GatewayBackendRefInstance) (httpRouteInstance model.HTTPRouteInstance) {
+
+	var httpRoute model.HTTPRouteInstance
+
+	httpRoute.APIVersion = GatewayAPIVersion
+	httpRoute.Kind = "HTTPRoute"
+	httpRoute.Metadata.Name = metadataName
+	httpRoute.Metadata.Namespace = namespace
+	httpRoute.Metadata.Labels = clusterLabels(metadataLabelCluster)
+	httpRoute.Spec.ParentRefs = serviceRoute(serviceName, port)
+	httpRoute.Spec.Rules = []model.HTTPRouteRuleInstance{{BackendRefs: backendRefs}}
+
+	return httpRoute
+}
+
+// Creates a GRPCRoute that splits the requests to the Service of a gRPC service between backends in a service mesh
+func CreateServiceGRPCRoute(metadataName, metadataLabelCluster, namespace, serviceName string, port int,
+	backendRefs []model.GatewayBackendRefInstance) (grpcRouteInstance model.GRPCRouteInstance) {
+
+	var grpcRoute model.GRPCRouteInstance
+
+	grpcRoute.APIVersion = GatewayAPIVersion
+	grpcRoute.Kind = "GRPCRoute"
+	grpcRoute.Metadata.Name = metadataName
+	grpcRoute.Metadata.Namespace = namespace
+	grpcRoute.Metadata.Labels = clusterLabels(metadataLabelCluster)
+	grpcRoute.Spec.ParentRefs = serviceRoute(serviceName, port)
+	grpcRoute.Spec.Rules = []model.GRPCRouteRuleInstance{{BackendRefs: backendRefs}}
+
+	return grpcRoute
+}
+
+// Creates a HorizontalPodAutoscaler that scales a Deployment or StatefulSet on CPU and memory utilization and a pods metric
+// Utilizations of 0 and a nil metric are left out
+func CreateHorizontalPodAutoscaler(metadataName, metadataLabelCluster, namespace, targetKind, targetName string,
+	minReplicas, maxReplicas, cpu, memory int, metric *model.AutoscalingMetric) (horizontalPodAutoscalerInstance model.HorizontalPodAutoscalerInstance) {
+
+	var hpa model.HorizontalPodAutoscalerInstance
+
+	resources := []struct {
+		name        string
+		utilization int
+	}{{"cpu", cpu}, {"memory", memory}}
+	for _, resource := range resources {
+		if resource.utilization == 0 {
+			continue
+		}
+		hpa.Spec.Metrics = append(hpa.Spec.Metrics, model.MetricSpecInstance{
+			Type: "Resource",
+			Resource: &model.ResourceMetricInstance{
+				Name:   resource.name,
+				Target: model.MetricTargetInstance{Type: "Utilization", AverageUtilization: resource.utilization},
+			},
+		})
+	}
+	if metric != nil {
+		pods := &model.PodsMetricInstance{Target: model.MetricTargetInstance{Type: "AverageValue", AverageValue: metric.Target}}
+		pods.Metric.Name = metric.Name
+		hpa.Spec.Metrics = append(hpa.Spec.Metrics, model.MetricSpecInstance{Type: "Pods", Pods: pods})
+	}
+
+	hpa.APIVersion = "autoscaling/v2"
+	hpa.Kind = "HorizontalPodAutoscaler"
+	hpa.Metadata.Name = metadataName
+	hpa.Metadata.Namespace = namespace
+	hpa.Metadata.Labels = clusterLabels(metadataLabelCluster)
+	hpa.Spec.ScaleTargetRef = model.ScaleTargetRefInstance{APIVersion: "apps/v1", Kind: targetKind, Name: targetName}
+	hpa.Spec.MinReplicas = minReplicas
+	hpa.Spec.MaxReplicas = maxReplicas
+
+	return hpa
+}
+
+// Creates a KEDA ScaledObject that scales a Deployment or StatefulSet on CPU and memory utilization and a Prometheus query
+// Utilizations of 0 and a nil metric are left out
+func CreateScaledObject(metadataName, metadataLabelCluster, namespace, targetKind, targetName string,
+	minReplicas, maxReplicas, cpu, memory int, metric *model.AutoscalingMetric, prometheusAddress string) (scaledObjectInstance model.ScaledObjectInstance) {
+
+	var scaledObject model.ScaledObjectInstance
+
+	resources := []struct {
+		name        string
+		utilization int
+	}{{"cpu", cpu}, {"memory", memory}}
+	for _, resource := range resources {
+		if resource.utilization == 0 {
+			continue
+		}
+		scaledObject.Spec.Triggers = append(scaledObject.Spec.Triggers, model.ScaledObjectTriggerInstance{
+			Type:       resource.name,
+			MetricType: "Utilization",
+			Metadata:   map[string]string{"value": strconv.Itoa(resource.utilization)},
+		})
+	}
+	if metric != nil {
+		scaledObject.Spec.Triggers = append(scaledObject.Spec.Triggers, model.ScaledObjectTriggerInstance{
+			Type: "prometheus",
+			Metadata: map[string]string{
+				"serverAddress": prometheusAddress,
+				"query":         metric.Query,
+				"threshold":     metric.Target,
+			},
+		})
+	}
+
+	scaledObject.APIVersion = "keda.sh/v1alpha1"
+	scaledObject.Kind = "ScaledObject"
+	scaledObject.Metadata.Name = metadataName
+	scaledObject.Metadata.Namespace = namespace
+	scaledObject.Metadata.Labels = clusterLabels(metadataLabelCluster)
+	scaledObject.Spec.ScaleTargetRef = model.ScaleTargetRefInstance{APIVersion: "apps/v1", Kind: targetKind, Name: targetName}
+	scaledObject.Spec.MinReplicaCount = minReplicas
+	scaledObject.Spec.MaxReplicaCount = maxReplicas
+
+	return scaledObject
+}
+
+// Creates a VerticalPodAutoscaler for the container of a Deployment or StatefulSet
+// The bounds of the resources are left out if they are not set
+func CreateVerticalPodAutoscaler(metadataName, metadataLabelCluster, namespace, targetKind, targetName, containerName, updateMode,
+	minCPU, minMemory, maxCPU, maxMemory, controlledValues string) (verticalPodAutoscalerInstance model.VerticalPodAutoscalerInstance) {
+
+	var vpa model.VerticalPodAutoscalerInstance
+
+	bounds := func(cpu, memory string) map[string]string {
+		resources := map[string]string{}
+		if cpu != "" {
+			resources["cpu"] = cpu
+		}
+		if memory != "" {
+			resources["memory"] = memory
+		}
+		return resources
+	}
+
+	vpa.APIVersion = "autoscaling.k8s.io/v1"
+	vpa.Kind = "VerticalPodAutoscaler"
+	vpa.Metadata.Name = metadataName
+	vpa.Metadata.Namespace = namespace
+	vpa.Metadata.Labels = clusterLabels(metadataLabelCluster)
+	vpa.Spec.TargetRef = model.ScaleTargetRefInstance{APIVersion: "apps/v1", Kind: targetKind, Name: targetName}
+	vpa.Spec.UpdatePolicy.UpdateMode = updateMode
+	vpa.Spec.ResourcePolicy.ContainerPolicies = append(vpa.Spec.ResourcePolicy.ContainerPolicies,
+		model.VerticalPodAutoscalerContainerPolicyInstance{
+			ContainerName:    containerName,
+			MinAllowed:       bounds(minCPU, minMemory),
+			MaxAllowed:       bounds(maxCPU, maxMemory),
+			ControlledValues: controlledValues,
+		})
+
+	return vpa
+}
+
 func CreateServiceAccount(metadataName, accountName string) (serviceAccountInstance model.ServiceAccountInstance) {
 	const apiVersion = "v1"
 	const apiKind = "ServiceAccount"
@@ -256,17 +1132,53 @@ func CreateFileConfig() model.FileConfig {
 	return fileConfig
 }
 
-func CreateConfigMap(processes int, logging bool, protocol string, ep []model.Endpoint) *model.ConfigMap {
+func CreateConfigMap(processes int, logging bool, deadlineReduction float32, limitCPU, storagePath string, disableOverrides bool,
+	protocol string, ep []model.Endpoint, health *model.Health) *model.ConfigMap {
+	// Without a CPU limit, the emulator uses all CPUs it can see
+	cpuLimit := 0.0
+	if limitCPU != "" {
+		cpuLimitResource, err := resource.ParseQuantity(limitCPU)
+		if err != nil {
+			panic(fmt.Errorf("Could not parse CPU limit %s: %s", limitCPU, err))
+		}
+		cpuLimit = cpuLimitResource.AsApproximateFloat64()
+	}
+
 	cm_data := &model.ConfigMap{
-		Processes: processes,
-		Logging:   logging,
-		Protocol:  protocol,
-		Endpoints: []model.Endpoint(ep),
+		Processes:         processes,
+		Logging:           logging,
+		DeadlineReduction: deadlineReduction,
+		CpuLimit:          cpuLimit,
+		StoragePath:       storagePath,
+		DisableOverrides:  disableOverrides,
+		Protocol:          protocol,
+		Health:            health,
+		Endpoints:         []model.Endpoint(ep),
 	}
 
 	return cm_data
 }
 
+// Returns the resource profiles that services can use without defining them in the settings
+func ResourceProfiles() map[string]model.Resources {
+	profile := func(requestCPU, requestMemory, limitCPU, limitMemory string) model.Resources {
+		var resources model.Resources
+		resources.Requests.Cpu = requestCPU
+		resources.Requests.Memory = requestMemory
+		resources.Limits.Cpu = limitCPU
+		resources.Limits.Memory = limitMemory
+		return resources
+	}
+
+	return map[string]model.Resources{
+		"small":        profile("100m", "64M", "250m", "256M"),
+		"medium":       profile(RequestsCPUDefault, RequestsMemoryDefault, LimitsCPUDefault, LimitsMemoryDefault),
+		"large":        profile("1000m", "1024M", "2000m", "4096M"),
+		"cpu-heavy":    profile("1000m", "256M", "2000m", "512M"),
+		"memory-heavy": profile("250m", "1024M", "500m", "4096M"),
+	}
+}
+
 func CreateInputResources() model.Resources {
 
 	var limits model.ResourceLimits
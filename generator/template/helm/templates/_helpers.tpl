@@ -0,0 +1,20 @@
+{{/*
+Returns a CPU quantity such as 500m as a number of CPUs
+*/}}
+{{- define "hydragen.cpus" -}}
+{{- $cpu := toString . -}}
+{{- if hasSuffix "m" $cpu -}}
+{{- divf (trimSuffix "m" $cpu) 1000 -}}
+{{- else -}}
+{{- $cpu -}}
+{{- end -}}
+{{- end -}}
+
+{{/*
+Labels of all resources, the cluster label selects the resources of a cluster
+*/}}
+{{- define "hydragen.labels" -}}
+cluster: {{ .Values.cluster }}
+app.kubernetes.io/managed-by: {{ .Release.Service }}
+helm.sh/chart: {{ .Chart.Name }}-{{ .Chart.Version }}
+{{- end -}}
@@ -0,0 +1,100 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+type ScaleTargetRefInstance struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Name       string `yaml:"name"`
+}
+
+type HorizontalPodAutoscalerInstance struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   MetadataInstance `yaml:"metadata"`
+	Spec       struct {
+		ScaleTargetRef ScaleTargetRefInstance `yaml:"scaleTargetRef"`
+		MinReplicas    int                    `yaml:"minReplicas"`
+		MaxReplicas    int                    `yaml:"maxReplicas"`
+		Metrics        []MetricSpecInstance   `yaml:"metrics"`
+	} `yaml:"spec"`
+}
+
+type MetricSpecInstance struct {
+	Type     string                  `yaml:"type"`
+	Resource *ResourceMetricInstance `yaml:"resource,omitempty"`
+	Pods     *PodsMetricInstance     `yaml:"pods,omitempty"`
+}
+
+type MetricTargetInstance struct {
+	Type               string `yaml:"type"`
+	AverageUtilization int    `yaml:"averageUtilization,omitempty"`
+	AverageValue       string `yaml:"averageValue,omitempty"`
+}
+
+type ResourceMetricInstance struct {
+	Name   string               `yaml:"name"`
+	Target MetricTargetInstance `yaml:"target"`
+}
+
+type PodsMetricInstance struct {
+	Metric struct {
+		Name string `yaml:"name"`
+	} `yaml:"metric"`
+	Target MetricTargetInstance `yaml:"target"`
+}
+
+// A KEDA ScaledObject, which creates and manages the HorizontalPodAutoscaler of its target
+type ScaledObjectInstance struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   MetadataInstance `yaml:"metadata"`
+	Spec       struct {
+		ScaleTargetRef  ScaleTargetRefInstance        `yaml:"scaleTargetRef"`
+		MinReplicaCount int                           `yaml:"minReplicaCount"`
+		MaxReplicaCount int                           `yaml:"maxReplicaCount"`
+		Triggers        []ScaledObjectTriggerInstance `yaml:"triggers"`
+	} `yaml:"spec"`
+}
+
+type ScaledObjectTriggerInstance struct {
+	Type       string            `yaml:"type"`
+	MetricType string            `yaml:"metricType,omitempty"`
+	Metadata   map[string]string `yaml:"metadata"`
+}
+
+type VerticalPodAutoscalerInstance struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   MetadataInstance `yaml:"metadata"`
+	Spec       struct {
+		TargetRef    ScaleTargetRefInstance `yaml:"targetRef"`
+		UpdatePolicy struct {
+			UpdateMode string `yaml:"updateMode"`
+		} `yaml:"updatePolicy"`
+		ResourcePolicy struct {
+			ContainerPolicies []VerticalPodAutoscalerContainerPolicyInstance `yaml:"containerPolicies"`
+		} `yaml:"resourcePolicy"`
+	} `yaml:"spec"`
+}
+
+type VerticalPodAutoscalerContainerPolicyInstance struct {
+	ContainerName    string            `yaml:"containerName"`
+	MinAllowed       map[string]string `yaml:"minAllowed,omitempty"`
+	MaxAllowed       map[string]string `yaml:"maxAllowed,omitempty"`
+	ControlledValues string            `yaml:"controlledValues"`
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+type ComposeInstance struct {
+	Services map[string]ComposeServiceInstance `yaml:"services"`
+	Networks map[string]ComposeNetworkInstance `yaml:"networks"`
+	Volumes  map[string]ComposeVolumeInstance  `yaml:"volumes,omitempty"`
+}
+
+type ComposeServiceInstance struct {
+	Image       string                                   `yaml:"image"`
+	PullPolicy  string                                   `yaml:"pull_policy,omitempty"`
+	Entrypoint  []string                                 `yaml:"entrypoint,omitempty"`
+	Command     []string                                 `yaml:"command,omitempty"`
+	Restart     string                                   `yaml:"restart,omitempty"`
+	Environment map[string]string                        `yaml:"environment,omitempty"`
+	Ports       []string                                 `yaml:"ports,omitempty"`
+	Volumes     []string                                 `yaml:"volumes"`
+	Networks    map[string]ComposeServiceNetworkInstance `yaml:"networks"`
+	DependsOn   []string                                 `yaml:"depends_on,omitempty"`
+	Deploy      ComposeDeployInstance                    `yaml:"deploy,omitempty"`
+}
+
+type ComposeServiceNetworkInstance struct {
+	Aliases []string `yaml:"aliases,omitempty"`
+}
+
+type ComposeDeployInstance struct {
+	Resources struct {
+		Limits       ComposeResourcesInstance `yaml:"limits,omitempty"`
+		Reservations ComposeResourcesInstance `yaml:"reservations,omitempty"`
+	} `yaml:"resources"`
+}
+
+type ComposeResourcesInstance struct {
+	Cpus   string `yaml:"cpus,omitempty"`
+	Memory string `yaml:"memory,omitempty"`
+}
+
+type ComposeNetworkInstance struct {
+	Name string `yaml:"name"`
+}
+
+type ComposeVolumeInstance struct{}
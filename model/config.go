@@ -23,9 +23,9 @@ type ConfigMapInstance struct {
 		Name   string `yaml:"name"`
 		Labels struct {
 			Name    string `yaml:"name"`
-			Cluster string `yaml:"version,omitempty"`
+			Cluster string `yaml:"cluster,omitempty"`
 		} `yaml:"labels"`
-		Namespace string `yaml:"namespace"`
+		Namespace string `yaml:"namespace,omitempty"`
 	} `yaml:"metadata"`
 	Data struct {
 		Config string `yaml:"conf.json"`
@@ -33,8 +33,15 @@ type ConfigMapInstance struct {
 }
 
 type ConfigMap struct {
-	Processes int        `json:"processes"`
-	Logging   bool       `json:"logging"`
-	Protocol  string     `json:"protocol"`
-	Endpoints []Endpoint `json:"endpoints"`
+	Processes         int               `json:"processes"`
+	Logging           bool              `json:"logging"`
+	DeadlineReduction float32           `json:"deadline_reduction,omitempty"`
+	CpuLimit          float64           `json:"cpu_limit,omitempty"`
+	StoragePath       string            `json:"storage_path,omitempty"`
+	DisableOverrides  bool              `json:"disable_overrides,omitempty"`
+	Port              int               `json:"port,omitempty"`
+	Addresses         map[string]string `json:"addresses,omitempty"`
+	Health            *Health           `json:"health,omitempty"`
+	Protocol          string            `json:"protocol"`
+	Endpoints         []Endpoint        `json:"endpoints"`
 }
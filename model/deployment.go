@@ -21,40 +21,72 @@ type DeploymentInstance struct {
 	Kind       string `yaml:"kind"`
 	Metadata   struct {
 		Name      string `yaml:"name"`
-		Namespace string `yaml:"namespace"`
+		Namespace string `yaml:"namespace,omitempty"`
 		Labels    struct {
 			App     string `yaml:"app,omitempty"`
-			Cluster string `yaml:"version,omitempty"`
+			Cluster string `yaml:"cluster,omitempty"`
 		} ` yaml:"labels"`
 	} `yaml:"metadata"`
 	Spec struct {
 		Selector struct {
 			MatchLabels struct {
 				App     string `yaml:"app"`
-				Cluster string `yaml:"version"`
+				Cluster string `yaml:"cluster,omitempty"`
+				Version string `yaml:"version,omitempty"`
 			} `yaml:"matchLabels"`
 		} `yaml:"selector"`
-		Replicas int `yaml:"replicas,omitempty"`
-		Template struct {
+		Replicas    int    `yaml:"replicas,omitempty"`
+		ServiceName string `yaml:"serviceName,omitempty"`
+		// StatefulSets are rolled out according to their update strategy
+		Strategy       *DeploymentStrategyInstance `yaml:"strategy,omitempty"`
+		UpdateStrategy *DeploymentStrategyInstance `yaml:"updateStrategy,omitempty"`
+		Template       struct {
 			Metadata struct {
 				Labels struct {
 					App     string `yaml:"app"`
-					Cluster string `yaml:"version"`
+					Cluster string `yaml:"cluster,omitempty"`
+					Version string `yaml:"version,omitempty"`
 				} `yaml:"labels"`
 				Annotations map[string]string `json:"annotations,omitempty"`
 			} `yaml:"metadata"`
 			Spec specInstance `yaml:"spec"`
 		} `yaml:"template"`
+		VolumeClaimTemplates []PersistentVolumeClaimInstance `yaml:"volumeClaimTemplates,omitempty"`
+	} `yaml:"spec"`
+}
+
+// Values are numbers of pods or percentages of the replicas
+type DeploymentStrategyInstance struct {
+	Type          string `yaml:"type"`
+	RollingUpdate struct {
+		MaxUnavailable interface{} `yaml:"maxUnavailable,omitempty"`
+		MaxSurge       interface{} `yaml:"maxSurge,omitempty"`
+	} `yaml:"rollingUpdate"`
+}
+
+type PersistentVolumeClaimInstance struct {
+	Metadata struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	Spec struct {
+		AccessModes      []string `yaml:"accessModes"`
+		StorageClassName string   `yaml:"storageClassName,omitempty"`
+		Resources        struct {
+			Requests struct {
+				Storage string `yaml:"storage"`
+			} `yaml:"requests"`
+		} `yaml:"resources"`
 	} `yaml:"spec"`
 }
 
 type DeploymentAnnotation map[string]string
 
 type specInstance struct {
-	NodeName       string              `yaml:"nodeName,omitempty"`
-	ServiceAccount string              `yaml:"serviceAccountName,omitempty"`
-	Containers     []ContainerInstance `yaml:"containers"`
-	Volumes        []VolumeInstance    `yaml:"volumes"`
+	NodeName           string `yaml:"nodeName,omitempty"`
+	SchedulingInstance `yaml:",inline"`
+	ServiceAccount     string              `yaml:"serviceAccountName,omitempty"`
+	Containers         []ContainerInstance `yaml:"containers"`
+	Volumes            []VolumeInstance    `yaml:"volumes"`
 }
 
 type VolumeInstance struct {
@@ -70,8 +102,8 @@ type ContainerInstance struct {
 	Env             []EnvInstance             `yaml:"env"`
 	Ports           []ContainerPortInstance   `yaml:"ports"`
 	Volumes         []ContainerVolumeInstance `yaml:"volumeMounts"`
-	ReadinessProbe  ReadinessProbeInstance    `yaml:"readinessProbe,omitempty"`
-	Resources       ResourcesInstance         `yaml:"resources"`
+	ProbesInstance  `yaml:",inline"`
+	Resources       ResourcesInstance `yaml:"resources"`
 }
 
 type ContainerPortInstance struct {
@@ -88,7 +120,13 @@ type ContainerVolumeInstance struct {
 	MountName string `yaml:"name,omitempty"`
 }
 
-type ReadinessProbeInstance struct {
+type ProbesInstance struct {
+	ReadinessProbe ProbeInstance  `yaml:"readinessProbe,omitempty"`
+	LivenessProbe  *ProbeInstance `yaml:"livenessProbe,omitempty"`
+	StartupProbe   *ProbeInstance `yaml:"startupProbe,omitempty"`
+}
+
+type ProbeInstance struct {
 	HttpGet struct {
 		Path string `yaml:"path,omitempty"`
 		Port int    `yaml:"port,omitempty"`
@@ -96,16 +134,19 @@ type ReadinessProbeInstance struct {
 	Exec struct {
 		Command []string `yaml:"command,flow,omitempty"`
 	} `yaml:"exec,omitempty"`
-	InitialDelaySeconds int `yaml:"initialDelaySeconds"`
-	PeriodSeconds       int `yaml:"periodSeconds"`
+	InitialDelaySeconds int `yaml:"initialDelaySeconds,omitempty"`
+	PeriodSeconds       int `yaml:"periodSeconds,omitempty"`
+	TimeoutSeconds      int `yaml:"timeoutSeconds,omitempty"`
+	FailureThreshold    int `yaml:"failureThreshold,omitempty"`
+	SuccessThreshold    int `yaml:"successThreshold,omitempty"`
 }
 type ResourcesInstance struct {
 	ResourceLimits struct {
-		Cpu    string `yaml:"cpu"`
-		Memory string `yaml:"memory"`
-	} `yaml:"limits"`
+		Cpu    string `yaml:"cpu,omitempty"`
+		Memory string `yaml:"memory,omitempty"`
+	} `yaml:"limits,omitempty"`
 	ResourceRequests struct {
-		Cpu    string `yaml:"cpu"`
-		Memory string `yaml:"memory"`
-	} `yaml:"requests"`
+		Cpu    string `yaml:"cpu,omitempty"`
+		Memory string `yaml:"memory,omitempty"`
+	} `yaml:"requests,omitempty"`
 }
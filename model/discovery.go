@@ -0,0 +1,34 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+// A Service that resolves to the DNS name of a service that is not deployed in the namespace
+type ExternalNameServiceInstance struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   MetadataInstance `yaml:"metadata"`
+	Spec       struct {
+		Type         string `yaml:"type"`
+		ExternalName string `yaml:"externalName"`
+	} `yaml:"spec"`
+}
+
+type ServiceExportInstance struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   MetadataInstance `yaml:"metadata"`
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+type IngressInstance struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   MetadataInstance `yaml:"metadata"`
+	Spec       struct {
+		IngressClassName string                `yaml:"ingressClassName,omitempty"`
+		Rules            []IngressRuleInstance `yaml:"rules"`
+	} `yaml:"spec"`
+}
+
+type IngressRuleInstance struct {
+	Host string `yaml:"host,omitempty"`
+	Http struct {
+		Paths []IngressPathInstance `yaml:"paths"`
+	} `yaml:"http"`
+}
+
+type IngressPathInstance struct {
+	Path     string `yaml:"path"`
+	PathType string `yaml:"pathType"`
+	Backend  struct {
+		Service struct {
+			Name string `yaml:"name"`
+			Port struct {
+				Number int `yaml:"number"`
+			} `yaml:"port"`
+		} `yaml:"service"`
+	} `yaml:"backend"`
+}
+
+// Routes attached to a Service have the core group, which is the empty string
+type GatewayParentRefInstance struct {
+	Group     *string `yaml:"group,omitempty"`
+	Kind      string  `yaml:"kind,omitempty"`
+	Name      string  `yaml:"name"`
+	Namespace string  `yaml:"namespace,omitempty"`
+	Port      int     `yaml:"port,omitempty"`
+}
+
+type GatewayBackendRefInstance struct {
+	Name   string `yaml:"name"`
+	Port   int    `yaml:"port"`
+	Weight int    `yaml:"weight,omitempty"`
+}
+
+type HTTPRouteInstance struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   MetadataInstance `yaml:"metadata"`
+	Spec       struct {
+		ParentRefs []GatewayParentRefInstance `yaml:"parentRefs"`
+		Hostnames  []string                   `yaml:"hostnames,omitempty"`
+		Rules      []HTTPRouteRuleInstance    `yaml:"rules"`
+	} `yaml:"spec"`
+}
+
+type HTTPRouteRuleInstance struct {
+	Matches     []HTTPRouteMatchInstance    `yaml:"matches,omitempty"`
+	BackendRefs []GatewayBackendRefInstance `yaml:"backendRefs"`
+}
+
+type HTTPRouteMatchInstance struct {
+	Path struct {
+		Type  string `yaml:"type"`
+		Value string `yaml:"value"`
+	} `yaml:"path"`
+}
+
+type GRPCRouteInstance struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   MetadataInstance `yaml:"metadata"`
+	Spec       struct {
+		ParentRefs []GatewayParentRefInstance `yaml:"parentRefs"`
+		Hostnames  []string                   `yaml:"hostnames,omitempty"`
+		Rules      []GRPCRouteRuleInstance    `yaml:"rules"`
+	} `yaml:"spec"`
+}
+
+type GRPCRouteRuleInstance struct {
+	Matches     []GRPCRouteMatchInstance    `yaml:"matches,omitempty"`
+	BackendRefs []GatewayBackendRefInstance `yaml:"backendRefs"`
+}
+
+type GRPCRouteMatchInstance struct {
+	Method struct {
+		Service string `yaml:"service"`
+		Method  string `yaml:"method"`
+	} `yaml:"method"`
+}
@@ -23,4 +23,7 @@ type EndpointResponse struct {
 	Status       string
 	Protocol     string
 	ResponseData *Response
+	// Number of duplicate requests sent and if one of them returned first
+	Hedges   int
+	HedgeWon bool
 }
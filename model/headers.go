@@ -0,0 +1,22 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+// Header containing the remaining time in milliseconds before the caller gives up on a request
+// gRPC propagates deadlines by itself but HTTP has no standard header for this
+// The load generator sets it and the application emulator aborts stressors and propagates the deadline when it receives it
+const TimeoutHeader = "X-Request-Timeout"
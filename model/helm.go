@@ -0,0 +1,111 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+type HelmChartInstance struct {
+	APIVersion  string `yaml:"apiVersion"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Type        string `yaml:"type"`
+	Version     string `yaml:"version"`
+	AppVersion  string `yaml:"appVersion"`
+}
+
+// Values shared by all clusters
+type HelmValuesInstance struct {
+	Image struct {
+		Repository string `yaml:"repository"`
+		Tag        string `yaml:"tag"`
+		PullPolicy string `yaml:"pullPolicy"`
+	} `yaml:"image"`
+	Logging  bool                                 `yaml:"logging"`
+	Cluster  string                               `yaml:"cluster"`
+	Services map[string]HelmServiceValuesInstance `yaml:"services"`
+	Workload *HelmWorkloadValuesInstance          `yaml:"workload,omitempty"`
+}
+
+type HelmServiceValuesInstance struct {
+	Enabled     bool                        `yaml:"enabled"`
+	Protocol    string                      `yaml:"protocol"`
+	Type        string                      `yaml:"type,omitempty"`
+	Namespace   string                      `yaml:"namespace"`
+	Replicas    int                         `yaml:"replicas"`
+	Strategy    map[string]interface{}      `yaml:"strategy,omitempty"`
+	Probes      map[string]interface{}      `yaml:"probes"`
+	Node        string                      `yaml:"node"`
+	Annotations map[string]string           `yaml:"annotations"`
+	Resources   HelmResourcesValuesInstance `yaml:"resources"`
+	Storage     *HelmStorageValuesInstance  `yaml:"storage,omitempty"`
+	Config      map[string]interface{}      `yaml:"config,omitempty"`
+	// Services with versions have a Deployment and config map for every version instead of the config above
+	Versions map[string]HelmVersionValuesInstance `yaml:"versions,omitempty"`
+}
+
+type HelmVersionValuesInstance struct {
+	Config map[string]interface{} `yaml:"config"`
+}
+
+type HelmResourcesValuesInstance struct {
+	Requests struct {
+		Cpu    string `yaml:"cpu,omitempty"`
+		Memory string `yaml:"memory,omitempty"`
+	} `yaml:"requests,omitempty"`
+	Limits struct {
+		Cpu    string `yaml:"cpu,omitempty"`
+		Memory string `yaml:"memory,omitempty"`
+	} `yaml:"limits,omitempty"`
+}
+
+type HelmStorageValuesInstance struct {
+	Size         string `yaml:"size"`
+	StorageClass string `yaml:"storageClass,omitempty"`
+}
+
+type HelmWorkloadValuesInstance struct {
+	Enabled   bool                   `yaml:"enabled"`
+	Kind      string                 `yaml:"kind"`
+	Namespace string                 `yaml:"namespace"`
+	Config    map[string]interface{} `yaml:"config"`
+}
+
+// Values that differ between clusters
+type HelmClusterValuesInstance struct {
+	Cluster  string                                      `yaml:"cluster"`
+	Services map[string]HelmClusterServiceValuesInstance `yaml:"services"`
+	Workload *HelmClusterWorkloadValuesInstance          `yaml:"workload,omitempty"`
+}
+
+type HelmClusterServiceValuesInstance struct {
+	Enabled     bool                   `yaml:"enabled"`
+	Namespace   string                 `yaml:"namespace"`
+	Replicas    int                    `yaml:"replicas"`
+	Node        string                 `yaml:"node,omitempty"`
+	Scheduling  map[string]interface{} `yaml:"scheduling,omitempty"`
+	Annotations map[string]string      `yaml:"annotations,omitempty"`
+	// Versions with their own number of replicas
+	Versions map[string]HelmClusterVersionValuesInstance `yaml:"versions,omitempty"`
+	// Manifests of the service that are only deployed on the cluster, such as mesh policies
+	Manifests []map[string]interface{} `yaml:"manifests,omitempty"`
+}
+
+type HelmClusterVersionValuesInstance struct {
+	Replicas int `yaml:"replicas"`
+}
+
+type HelmClusterWorkloadValuesInstance struct {
+	Enabled bool `yaml:"enabled"`
+}
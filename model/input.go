@@ -16,31 +16,85 @@ limitations under the License.
 
 package model
 
+type HedgingPolicy struct {
+	Delay      float32 `json:"delay,omitempty"`
+	Percentile float32 `json:"percentile,omitempty"`
+	MaxHedges  int     `json:"max_hedges,omitempty"`
+}
+
 type CalledService struct {
-	Service             string `json:"service"`
-	Port                int    `json:"port"`
-	Endpoint            string `json:"endpoint"`
-	Protocol            string `json:"protocol"`
-	TrafficForwardRatio int    `json:"traffic_forward_ratio"`
-	RequestPayloadSize  int    `json:"request_payload_size"`
+	Service                    string         `json:"service"`
+	Port                       int            `json:"port"`
+	Endpoint                   string         `json:"endpoint"`
+	Protocol                   string         `json:"protocol"`
+	TrafficForwardRatio        int            `json:"traffic_forward_ratio"`
+	RequestPayloadSize         int            `json:"request_payload_size"`
+	RequestPayloadContent      string         `json:"request_payload_content,omitempty"`
+	RequestPayloadCompressible bool           `json:"request_payload_compressible,omitempty"`
+	Hedging                    *HedgingPolicy `json:"hedging,omitempty"`
 }
 
 type CpuComplexity struct {
-	ExecutionTime float32 `json:"execution_time"`
-	Threads       int     `json:"threads"`
+	Mode           string  `json:"mode,omitempty"`
+	ExecutionTime  float32 `json:"execution_time"`
+	WorkUnits      float32 `json:"work_units,omitempty"`
+	Utilization    float32 `json:"utilization,omitempty"`
+	Kernel         string  `json:"kernel,omitempty"`
+	WorkingSetSize int     `json:"working_set_size,omitempty"`
+	Threads        int     `json:"threads"`
+}
+
+type LockComplexity struct {
+	Locks    int     `json:"locks"`
+	HoldTime float32 `json:"hold_time"`
+	HoldMode string  `json:"hold_mode,omitempty"`
 }
 
 type NetworkComplexity struct {
-	ForwardRequests     string          `json:"forward_requests"`
-	ResponsePayloadSize int             `json:"response_payload_size"`
-	CalledServices      []CalledService `json:"called_services"`
+	ForwardRequests             string          `json:"forward_requests"`
+	ResponsePayloadSize         int             `json:"response_payload_size"`
+	ResponsePayloadContent      string          `json:"response_payload_content,omitempty"`
+	ResponsePayloadCompressible bool            `json:"response_payload_compressible,omitempty"`
+	EchoPayload                 bool            `json:"echo_payload,omitempty"`
+	CalledServices              []CalledService `json:"called_services"`
+}
+
+type PayloadComplexity struct {
+	ContentType  string  `json:"content_type,omitempty"`
+	Validate     bool    `json:"validate,omitempty"`
+	Hash         bool    `json:"hash,omitempty"`
+	Transform    string  `json:"transform,omitempty"`
+	CpuTimePerKb float32 `json:"cpu_time_per_kb,omitempty"`
+}
+
+type StorageComplexity struct {
+	Keys         int     `json:"keys"`
+	ValueSize    int     `json:"value_size"`
+	Operations   int     `json:"operations"`
+	ReadRatio    float32 `json:"read_ratio"`
+	Distribution string  `json:"distribution"`
+	ZipfExponent float64 `json:"zipf_exponent,omitempty"`
+	Persist      bool    `json:"persist,omitempty"`
+}
+
+type Step struct {
+	PayloadComplexity *PayloadComplexity `json:"payload_complexity,omitempty"`
+	CpuComplexity     *CpuComplexity     `json:"cpu_complexity,omitempty"`
+	LockComplexity    *LockComplexity    `json:"lock_complexity,omitempty"`
+	StorageComplexity *StorageComplexity `json:"storage_complexity,omitempty"`
+	NetworkComplexity *NetworkComplexity `json:"network_complexity,omitempty"`
 }
 
 type Endpoint struct {
 	Name              string             `json:"name"`
 	ExecutionMode     string             `json:"execution_mode"`
+	EntryPoint        bool               `json:"entry_point,omitempty"`
+	PayloadComplexity *PayloadComplexity `json:"payload_complexity,omitempty"`
 	CpuComplexity     *CpuComplexity     `json:"cpu_complexity,omitempty"`
+	LockComplexity    *LockComplexity    `json:"lock_complexity,omitempty"`
+	StorageComplexity *StorageComplexity `json:"storage_complexity,omitempty"`
 	NetworkComplexity *NetworkComplexity `json:"network_complexity,omitempty"`
+	Steps             []Step             `json:"steps,omitempty"`
 }
 
 type ResourceLimits struct {
@@ -58,22 +112,141 @@ type Resources struct {
 	Requests ResourceRequests `json:"requests"`
 }
 
+type Storage struct {
+	Size         string `json:"size"`
+	StorageClass string `json:"storage_class,omitempty"`
+}
+
+type AutoscalingMetric struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+	Query  string `json:"query,omitempty"`
+}
+
+type Autoscaling struct {
+	Kind              string             `json:"kind"`
+	MinReplicas       int                `json:"min_replicas"`
+	MaxReplicas       int                `json:"max_replicas"`
+	Cpu               int                `json:"cpu,omitempty"`
+	Memory            int                `json:"memory,omitempty"`
+	Metric            *AutoscalingMetric `json:"metric,omitempty"`
+	PrometheusAddress string             `json:"prometheus_address,omitempty"`
+}
+
+type VerticalScaling struct {
+	Mode             string `json:"mode"`
+	MinCpu           string `json:"min_cpu,omitempty"`
+	MinMemory        string `json:"min_memory,omitempty"`
+	MaxCpu           string `json:"max_cpu,omitempty"`
+	MaxMemory        string `json:"max_memory,omitempty"`
+	ControlledValues string `json:"controlled_values"`
+}
+
+type Probe struct {
+	InitialDelay     int `json:"initial_delay,omitempty"`
+	Period           int `json:"period,omitempty"`
+	Timeout          int `json:"timeout,omitempty"`
+	FailureThreshold int `json:"failure_threshold,omitempty"`
+	SuccessThreshold int `json:"success_threshold,omitempty"`
+}
+
+type Probes struct {
+	Readiness *Probe `json:"readiness,omitempty"`
+	Liveness  *Probe `json:"liveness,omitempty"`
+	Startup   *Probe `json:"startup,omitempty"`
+}
+
+// Ways the health checks of a service fail on purpose
+type Health struct {
+	StartupDelay   float64 `json:"startup_delay,omitempty"`
+	MaxInFlight    int     `json:"max_in_flight,omitempty"`
+	UnreadyAfter   int     `json:"unready_after,omitempty"`
+	UnhealthyAfter int     `json:"unhealthy_after,omitempty"`
+}
+
 type Service struct {
-	Name           string     `json:"name"`
-	Clusters       []Cluster  `json:"clusters"`
-	Resources      Resources  `json:"resources"`
-	Processes      int        `json:"processes"`
-	ReadinessProbe int        `json:"readiness_probe"`
-	Protocol       string     `json:"protocol"`
-	Endpoints      []Endpoint `json:"endpoints"`
+	Name             string           `json:"name"`
+	Clusters         []Cluster        `json:"clusters"`
+	Resources        Resources        `json:"resources"`
+	Profile          string           `json:"profile,omitempty"`
+	QosClass         string           `json:"qos_class,omitempty"`
+	Processes        int              `json:"processes"`
+	ReadinessProbe   int              `json:"readiness_probe"`
+	Probes           *Probes          `json:"probes,omitempty"`
+	Health           *Health          `json:"health,omitempty"`
+	Protocol         string           `json:"protocol"`
+	Storage          *Storage         `json:"storage,omitempty"`
+	DisableOverrides bool             `json:"disable_overrides,omitempty"`
+	Autoscaling      *Autoscaling     `json:"autoscaling,omitempty"`
+	VerticalScaling  *VerticalScaling `json:"vertical_scaling,omitempty"`
+	Rollout          *Rollout         `json:"rollout,omitempty"`
+	Versions         []Version        `json:"versions,omitempty"`
+	Endpoints        []Endpoint       `json:"endpoints"`
+}
+
+// How the pods of a service are replaced and evicted
+// Values are a number of pods or a percentage of the replicas, such as "1" or "25%"
+type Rollout struct {
+	MaxUnavailable   string            `json:"max_unavailable,omitempty"`
+	MaxSurge         string            `json:"max_surge,omitempty"`
+	DisruptionBudget *DisruptionBudget `json:"disruption_budget,omitempty"`
+}
+
+type DisruptionBudget struct {
+	MinAvailable   string `json:"min_available,omitempty"`
+	MaxUnavailable string `json:"max_unavailable,omitempty"`
+}
+
+// A version of a service, which runs as its own Deployment behind the Kubernetes service of the service
+// Weights are the percentage of requests that a version gets, versions without a weight get none unless no version has one
+type Version struct {
+	Name      string     `json:"name"`
+	Weight    int        `json:"weight,omitempty"`
+	Replicas  int        `json:"replicas,omitempty"`
+	Endpoints []Endpoint `json:"endpoints,omitempty"`
+}
+
+type NodeAffinityRule struct {
+	Key      string   `json:"key"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values,omitempty"`
+	Weight   int      `json:"weight,omitempty"`
+}
+
+type ServiceAffinityRule struct {
+	Service     string `json:"service"`
+	TopologyKey string `json:"topology_key"`
+	Weight      int    `json:"weight,omitempty"`
+}
+
+type Toleration struct {
+	Key               string `json:"key,omitempty"`
+	Operator          string `json:"operator"`
+	Value             string `json:"value,omitempty"`
+	Effect            string `json:"effect,omitempty"`
+	TolerationSeconds *int   `json:"toleration_seconds,omitempty"`
+}
+
+type TopologySpread struct {
+	TopologyKey       string `json:"topology_key"`
+	MaxSkew           int    `json:"max_skew"`
+	WhenUnsatisfiable string `json:"when_unsatisfiable"`
 }
 
 type Cluster struct {
-	Cluster     string       `json:"cluster"`
-	Replicas    int          `json:"replicas,omitempty"`
-	Namespace   string       `json:"namespace"`
-	Node        string       `json:"node,omitempty"`
-	Annotations []Annotation `json:"annotations,omitempty"`
+	Cluster             string                `json:"cluster"`
+	Replicas            int                   `json:"replicas,omitempty"`
+	Namespace           string                `json:"namespace"`
+	Node                string                `json:"node,omitempty"`
+	NodeSelector        map[string]string     `json:"node_selector,omitempty"`
+	NodeAffinity        []NodeAffinityRule    `json:"node_affinity,omitempty"`
+	ServiceAffinity     []ServiceAffinityRule `json:"service_affinity,omitempty"`
+	ServiceAntiAffinity []ServiceAffinityRule `json:"service_anti_affinity,omitempty"`
+	Tolerations         []Toleration          `json:"tolerations,omitempty"`
+	TopologySpread      []TopologySpread      `json:"topology_spread,omitempty"`
+	PriorityClass       string                `json:"priority_class,omitempty"`
+	Annotations         []Annotation          `json:"annotations,omitempty"`
+	Autoscaling         *Autoscaling          `json:"autoscaling,omitempty"`
 }
 
 type Annotation struct {
@@ -88,15 +261,107 @@ type ClusterLatency struct {
 }
 
 type Setting struct {
-	Logging     bool   `json:"logging"`
-	Development bool   `json:"development"`
-	BaseImage   string `json:"base_image"`
+	Logging           bool                 `json:"logging"`
+	Development       bool                 `json:"development"`
+	BaseImage         string               `json:"base_image"`
+	DeadlineReduction float32              `json:"deadline_reduction,omitempty"`
+	Profiles          map[string]Resources `json:"profiles,omitempty"`
+	Discovery         *Discovery           `json:"discovery,omitempty"`
+	NetworkPolicies   bool                 `json:"network_policies,omitempty"`
+	Gamma             bool                 `json:"gamma,omitempty"`
+}
+
+// How services are reached from clusters where they are not deployed
+type Discovery struct {
+	Method    string            `json:"method"`
+	Addresses map[string]string `json:"addresses,omitempty"`
+}
+
+type WorkloadEndpoint struct {
+	Service  string  `json:"service"`
+	Endpoint string  `json:"endpoint"`
+	Weight   float64 `json:"weight,omitempty"`
+}
+
+type Workload struct {
+	Kind         string             `json:"kind"`
+	Cluster      string             `json:"cluster"`
+	Namespace    string             `json:"namespace"`
+	Endpoints    []WorkloadEndpoint `json:"endpoints"`
+	Pattern      string             `json:"pattern"`
+	Duration     float64            `json:"duration"`
+	Users        int                `json:"users,omitempty"`
+	ThinkTime    float64            `json:"think_time,omitempty"`
+	Rate         float64            `json:"rate,omitempty"`
+	EndRate      float64            `json:"end_rate,omitempty"`
+	StepRate     float64            `json:"step_rate,omitempty"`
+	StepDuration float64            `json:"step_duration,omitempty"`
+	Trace        []float64          `json:"trace,omitempty"`
+	MaxInFlight  int                `json:"max_in_flight"`
+	Timeout      float64            `json:"timeout"`
+	PayloadSize  int                `json:"payload_size,omitempty"`
+	Headers      map[string]string  `json:"headers,omitempty"`
+	Interval     float64            `json:"interval"`
+	Format       string             `json:"format"`
+}
+
+type ConnectionPool struct {
+	MaxConnections           int     `json:"max_connections,omitempty"`
+	ConnectTimeout           float64 `json:"connect_timeout,omitempty"`
+	MaxPendingRequests       int     `json:"max_pending_requests,omitempty"`
+	MaxRequests              int     `json:"max_requests,omitempty"`
+	MaxRequestsPerConnection int     `json:"max_requests_per_connection,omitempty"`
+}
+
+type OutlierDetection struct {
+	ConsecutiveErrors  int     `json:"consecutive_errors,omitempty"`
+	Interval           float64 `json:"interval,omitempty"`
+	BaseEjectionTime   float64 `json:"base_ejection_time,omitempty"`
+	MaxEjectionPercent int     `json:"max_ejection_percent,omitempty"`
+}
+
+type Retries struct {
+	Attempts      int     `json:"attempts"`
+	PerTryTimeout float64 `json:"per_try_timeout,omitempty"`
+	RetryOn       string  `json:"retry_on,omitempty"`
+}
+
+type MeshPolicy struct {
+	Mtls             string            `json:"mtls,omitempty"`
+	LoadBalancer     string            `json:"load_balancer,omitempty"`
+	ConnectionPool   *ConnectionPool   `json:"connection_pool,omitempty"`
+	OutlierDetection *OutlierDetection `json:"outlier_detection,omitempty"`
+	Timeout          float64           `json:"timeout,omitempty"`
+	Retries          *Retries          `json:"retries,omitempty"`
+}
+
+type MeshService struct {
+	Service string `json:"service"`
+	MeshPolicy
+}
+
+type Mesh struct {
+	MeshPolicy
+	DisableInjection bool          `json:"disable_injection,omitempty"`
+	Services         []MeshService `json:"services,omitempty"`
+}
+
+// How entry point endpoints are reached from outside the cluster
+type EntryPoints struct {
+	Kind             string `json:"kind"`
+	Class            string `json:"class,omitempty"`
+	Host             string `json:"host,omitempty"`
+	Gateway          string `json:"gateway,omitempty"`
+	GatewayNamespace string `json:"gateway_namespace,omitempty"`
 }
 
 type FileConfig struct {
 	ClusterLatencies []ClusterLatency `json:"cluster_latencies"`
 	Services         []Service        `json:"services"`
 	Settings         Setting          `json:"settings,omitempty"`
+	Workload         *Workload        `json:"workload,omitempty"`
+	Mesh             *Mesh            `json:"mesh,omitempty"`
+	EntryPoints      *EntryPoints     `json:"entry_points,omitempty"`
 }
 
 type UserConfig struct {
@@ -0,0 +1,52 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+type KustomizationInstance struct {
+	APIVersion string                     `yaml:"apiVersion"`
+	Kind       string                     `yaml:"kind"`
+	Namespace  string                     `yaml:"namespace,omitempty"`
+	Resources  []string                   `yaml:"resources"`
+	Labels     []KustomizeLabelInstance   `yaml:"labels,omitempty"`
+	Replicas   []KustomizeReplicaInstance `yaml:"replicas,omitempty"`
+	Patches    []KustomizePatchInstance   `yaml:"patches,omitempty"`
+}
+
+type KustomizeLabelInstance struct {
+	Pairs            map[string]string `yaml:"pairs"`
+	IncludeSelectors bool              `yaml:"includeSelectors"`
+}
+
+type KustomizeReplicaInstance struct {
+	Name  string `yaml:"name"`
+	Count int    `yaml:"count"`
+}
+
+type KustomizePatchInstance struct {
+	Target struct {
+		Kind string `yaml:"kind"`
+		Name string `yaml:"name"`
+	} `yaml:"target"`
+	Patch string `yaml:"patch"`
+}
+
+// A JSON patch operation
+type KustomizePatchOperationInstance struct {
+	Op    string      `yaml:"op"`
+	Path  string      `yaml:"path"`
+	Value interface{} `yaml:"value"`
+}
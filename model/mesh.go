@@ -0,0 +1,122 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+// Metadata of manifests that only need a name, namespace and labels
+type MetadataInstance struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+}
+
+type NamespaceInstance struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   MetadataInstance `yaml:"metadata"`
+}
+
+type VirtualServiceInstance struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   MetadataInstance `yaml:"metadata"`
+	Spec       struct {
+		Hosts []string                      `yaml:"hosts"`
+		Http  []VirtualServiceRouteInstance `yaml:"http"`
+	} `yaml:"spec"`
+}
+
+type VirtualServiceRouteInstance struct {
+	Route   []VirtualServiceDestinationInstance `yaml:"route"`
+	Timeout string                              `yaml:"timeout,omitempty"`
+	Retries *VirtualServiceRetriesInstance      `yaml:"retries,omitempty"`
+}
+
+type VirtualServiceDestinationInstance struct {
+	Destination struct {
+		Host   string `yaml:"host"`
+		Subset string `yaml:"subset,omitempty"`
+		Port   struct {
+			Number int `yaml:"number"`
+		} `yaml:"port"`
+	} `yaml:"destination"`
+	Weight int `yaml:"weight,omitempty"`
+}
+
+type VirtualServiceRetriesInstance struct {
+	Attempts      int    `yaml:"attempts"`
+	PerTryTimeout string `yaml:"perTryTimeout,omitempty"`
+	RetryOn       string `yaml:"retryOn,omitempty"`
+}
+
+type DestinationRuleInstance struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   MetadataInstance `yaml:"metadata"`
+	Spec       struct {
+		Host          string                      `yaml:"host"`
+		TrafficPolicy TrafficPolicyInstance       `yaml:"trafficPolicy"`
+		Subsets       []DestinationSubsetInstance `yaml:"subsets,omitempty"`
+	} `yaml:"spec"`
+}
+
+type DestinationSubsetInstance struct {
+	Name   string            `yaml:"name"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+type TrafficPolicyInstance struct {
+	LoadBalancer     *LoadBalancerInstance     `yaml:"loadBalancer,omitempty"`
+	ConnectionPool   *ConnectionPoolInstance   `yaml:"connectionPool,omitempty"`
+	OutlierDetection *OutlierDetectionInstance `yaml:"outlierDetection,omitempty"`
+}
+
+type LoadBalancerInstance struct {
+	Simple string `yaml:"simple"`
+}
+
+type ConnectionPoolInstance struct {
+	Tcp struct {
+		MaxConnections int    `yaml:"maxConnections,omitempty"`
+		ConnectTimeout string `yaml:"connectTimeout,omitempty"`
+	} `yaml:"tcp,omitempty"`
+	Http struct {
+		Http1MaxPendingRequests  int `yaml:"http1MaxPendingRequests,omitempty"`
+		Http2MaxRequests         int `yaml:"http2MaxRequests,omitempty"`
+		MaxRequestsPerConnection int `yaml:"maxRequestsPerConnection,omitempty"`
+	} `yaml:"http,omitempty"`
+}
+
+type OutlierDetectionInstance struct {
+	Consecutive5xxErrors int    `yaml:"consecutive5xxErrors,omitempty"`
+	Interval             string `yaml:"interval,omitempty"`
+	BaseEjectionTime     string `yaml:"baseEjectionTime,omitempty"`
+	MaxEjectionPercent   int    `yaml:"maxEjectionPercent,omitempty"`
+}
+
+type PeerAuthenticationInstance struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   MetadataInstance `yaml:"metadata"`
+	Spec       struct {
+		Selector struct {
+			MatchLabels map[string]string `yaml:"matchLabels"`
+		} `yaml:"selector"`
+		Mtls struct {
+			Mode string `yaml:"mode"`
+		} `yaml:"mtls"`
+	} `yaml:"spec"`
+}
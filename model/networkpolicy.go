@@ -0,0 +1,45 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+type NetworkPolicyInstance struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   MetadataInstance `yaml:"metadata"`
+	Spec       struct {
+		PodSelector LabelSelectorInstance              `yaml:"podSelector"`
+		PolicyTypes []string                           `yaml:"policyTypes"`
+		Ingress     []NetworkPolicyIngressRuleInstance `yaml:"ingress"`
+	} `yaml:"spec"`
+}
+
+// A rule without peers allows traffic from any source to its ports
+type NetworkPolicyIngressRuleInstance struct {
+	From  []NetworkPolicyPeerInstance `yaml:"from,omitempty"`
+	Ports []NetworkPolicyPortInstance `yaml:"ports"`
+}
+
+// A peer without a namespace selector only selects pods in the namespace of the policy
+type NetworkPolicyPeerInstance struct {
+	PodSelector       *LabelSelectorInstance `yaml:"podSelector,omitempty"`
+	NamespaceSelector *LabelSelectorInstance `yaml:"namespaceSelector,omitempty"`
+}
+
+type NetworkPolicyPortInstance struct {
+	Protocol string `yaml:"protocol"`
+	Port     int    `yaml:"port"`
+}
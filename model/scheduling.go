@@ -0,0 +1,91 @@
+/*
+Copyright 2023 Telefonaktiebolaget LM Ericsson AB
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+// Scheduling constraints of the pods of a Deployment or StatefulSet
+type SchedulingInstance struct {
+	NodeSelector              map[string]string                  `yaml:"nodeSelector,omitempty"`
+	Affinity                  *AffinityInstance                  `yaml:"affinity,omitempty"`
+	Tolerations               []TolerationInstance               `yaml:"tolerations,omitempty"`
+	TopologySpreadConstraints []TopologySpreadConstraintInstance `yaml:"topologySpreadConstraints,omitempty"`
+	PriorityClassName         string                             `yaml:"priorityClassName,omitempty"`
+}
+
+type AffinityInstance struct {
+	NodeAffinity    *NodeAffinityInstance `yaml:"nodeAffinity,omitempty"`
+	PodAffinity     *PodAffinityInstance  `yaml:"podAffinity,omitempty"`
+	PodAntiAffinity *PodAffinityInstance  `yaml:"podAntiAffinity,omitempty"`
+}
+
+type NodeAffinityInstance struct {
+	Required  *NodeSelectorInstance             `yaml:"requiredDuringSchedulingIgnoredDuringExecution,omitempty"`
+	Preferred []PreferredSchedulingTermInstance `yaml:"preferredDuringSchedulingIgnoredDuringExecution,omitempty"`
+}
+
+type NodeSelectorInstance struct {
+	NodeSelectorTerms []NodeSelectorTermInstance `yaml:"nodeSelectorTerms"`
+}
+
+type NodeSelectorTermInstance struct {
+	MatchExpressions []NodeSelectorRequirementInstance `yaml:"matchExpressions"`
+}
+
+type NodeSelectorRequirementInstance struct {
+	Key      string   `yaml:"key"`
+	Operator string   `yaml:"operator"`
+	Values   []string `yaml:"values,omitempty"`
+}
+
+type PreferredSchedulingTermInstance struct {
+	Weight     int                      `yaml:"weight"`
+	Preference NodeSelectorTermInstance `yaml:"preference"`
+}
+
+type PodAffinityInstance struct {
+	Required  []PodAffinityTermInstance         `yaml:"requiredDuringSchedulingIgnoredDuringExecution,omitempty"`
+	Preferred []WeightedPodAffinityTermInstance `yaml:"preferredDuringSchedulingIgnoredDuringExecution,omitempty"`
+}
+
+type LabelSelectorInstance struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+type PodAffinityTermInstance struct {
+	LabelSelector LabelSelectorInstance `yaml:"labelSelector"`
+	Namespaces    []string              `yaml:"namespaces,omitempty"`
+	TopologyKey   string                `yaml:"topologyKey"`
+}
+
+type WeightedPodAffinityTermInstance struct {
+	Weight          int                     `yaml:"weight"`
+	PodAffinityTerm PodAffinityTermInstance `yaml:"podAffinityTerm"`
+}
+
+type TolerationInstance struct {
+	Key               string `yaml:"key,omitempty"`
+	Operator          string `yaml:"operator,omitempty"`
+	Value             string `yaml:"value,omitempty"`
+	Effect            string `yaml:"effect,omitempty"`
+	TolerationSeconds *int   `yaml:"tolerationSeconds,omitempty"`
+}
+
+type TopologySpreadConstraintInstance struct {
+	MaxSkew           int                   `yaml:"maxSkew"`
+	TopologyKey       string                `yaml:"topologyKey"`
+	WhenUnsatisfiable string                `yaml:"whenUnsatisfiable"`
+	LabelSelector     LabelSelectorInstance `yaml:"labelSelector"`
+}
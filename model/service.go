@@ -21,18 +21,20 @@ type ServiceInstance struct {
 	Kind       string `yaml:"kind"`
 	Metadata   struct {
 		Name      string `yaml:"name"`
-		Namespace string `yaml:"namespace"`
+		Namespace string `yaml:"namespace,omitempty"`
 		Labels    struct {
-			Cluster string `yaml:"version,omitempty"`
+			Cluster string `yaml:"cluster,omitempty"`
 		} ` yaml:"labels"`
 		Annotations map[string]string `json:"annotations,omitempty"`
 	} `yaml:"metadata"`
 	Spec struct {
 		Selector struct {
-			App string `yaml:"app"`
+			App     string `yaml:"app"`
+			Version string `yaml:"version,omitempty"`
 		} `yaml:"selector"`
-		Ports []ServicePortInstance `yaml:"ports"`
-		Type  string                `yaml:"type,omitempty"`
+		Ports     []ServicePortInstance `yaml:"ports"`
+		Type      string                `yaml:"type,omitempty"`
+		ClusterIP string                `yaml:"clusterIP,omitempty"`
 	} `yaml:"spec"`
 }
 
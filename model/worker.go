@@ -17,12 +17,10 @@ limitations under the License.
 package model
 
 type WorkerDeploymentInstance struct {
-	APIVersion string `yaml:"apiVersion"`
-	Kind       string `yaml:"kind"`
-	Metadata   struct {
-		Name string `yaml:"name"`
-	} `yaml:"metadata"`
-	Spec struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   MetadataInstance `yaml:"metadata"`
+	Spec       struct {
 		Selector struct {
 			MatchLabels struct {
 				App string `yaml:"app"`
@@ -32,7 +30,8 @@ type WorkerDeploymentInstance struct {
 		Template struct {
 			Metadata struct {
 				Labels struct {
-					App string `yaml:"app"`
+					App     string `yaml:"app"`
+					Cluster string `yaml:"cluster,omitempty"`
 				} `yaml:"labels"`
 			} `yaml:"metadata"`
 			Spec struct {
@@ -47,5 +46,29 @@ type WorkerContainerInstance struct {
 	Name            string                    `yaml:"name"`
 	Image           string                    `yaml:"image"`
 	ImagePullPolicy string                    `yaml:"imagePullPolicy"`
+	Command         []string                  `yaml:"command,omitempty"`
+	Args            []string                  `yaml:"args,omitempty"`
 	Volumes         []ContainerVolumeInstance `yaml:"volumeMounts"`
 }
+
+type WorkerJobInstance struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   MetadataInstance `yaml:"metadata"`
+	Spec       struct {
+		BackoffLimit int `yaml:"backoffLimit"`
+		Template     struct {
+			Metadata struct {
+				Labels struct {
+					App     string `yaml:"app"`
+					Cluster string `yaml:"cluster,omitempty"`
+				} `yaml:"labels"`
+			} `yaml:"metadata"`
+			Spec struct {
+				RestartPolicy string                    `yaml:"restartPolicy"`
+				Containers    []WorkerContainerInstance `yaml:"containers"`
+				Volumes       []VolumeInstance          `yaml:"volumes"`
+			} `yaml:"spec"`
+		} `yaml:"template"`
+	} `yaml:"spec"`
+}